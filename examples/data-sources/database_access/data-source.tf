@@ -0,0 +1,3 @@
+data "influxdb3_database_access" "signals" {
+  database = "signals"
+}
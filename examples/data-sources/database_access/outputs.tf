@@ -0,0 +1,3 @@
+output "signals_access" {
+  value = data.influxdb3_database_access.signals.tokens
+}
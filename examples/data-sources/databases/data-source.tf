@@ -1 +1,5 @@
 data "influxdb3_databases" "all" {}
+
+data "influxdb3_databases" "signals" {
+  name_regex = "^signals"
+}
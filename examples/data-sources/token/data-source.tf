@@ -1,3 +1,7 @@
 data "influxdb3_token" "signals_token" {
   id = "7f7fa77d-b77e-77ba-7777-77cd077d0f7c"
 }
+
+data "influxdb3_token" "shared_ingest_token" {
+  description = "Shared ingest token"
+}
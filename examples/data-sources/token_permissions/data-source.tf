@@ -0,0 +1,19 @@
+data "influxdb3_token_permissions" "shared_ingest" {
+  statements = [
+    {
+      sid       = "signals"
+      actions   = ["read", "write"]
+      databases = ["signals"]
+    },
+    {
+      sid               = "telemetry"
+      actions           = ["write"]
+      database_patterns = ["^telemetry_"]
+    },
+  ]
+}
+
+resource "influxdb3_token" "shared_ingest" {
+  description = "Shared ingest token"
+  permissions = data.influxdb3_token_permissions.shared_ingest.permissions
+}
@@ -0,0 +1,3 @@
+output "shared_ingest_permissions" {
+  value = data.influxdb3_token_permissions.shared_ingest.permissions
+}
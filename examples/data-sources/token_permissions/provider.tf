@@ -0,0 +1,9 @@
+terraform {
+  required_providers {
+    influxdb3 = {
+      source = "thulasirajkomminar/influxdb3"
+    }
+  }
+}
+
+provider "influxdb3" {}
@@ -1 +1,7 @@
 data "influxdb3_tokens" "all" {}
+
+data "influxdb3_tokens" "signals_writers" {
+  database = "signals"
+  action   = "write"
+  expired  = false
+}
@@ -16,3 +16,23 @@ resource "influxdb3_token" "signals" {
     }
   ]
 }
+
+resource "influxdb3_token" "signals_shorthand" {
+  description = "Read signals and write alerts"
+
+  read_databases  = [data.influxdb3_database.signals.name]
+  write_databases = ["alerts"]
+}
+
+resource "influxdb3_token" "signals_rotating" {
+  description = "Rotating write access to signals database"
+
+  write_databases = [data.influxdb3_database.signals.name]
+
+  expires_in    = "720h"
+  rotate_before = "168h"
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
@@ -0,0 +1,259 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// AUDIT_LOG_REDACTED replaces secrets in the audit log.
+const AUDIT_LOG_REDACTED = "REDACTED"
+
+// auditLog appends a JSON line to a local file for every mutating API call.
+// Each line carries the SHA-256 hash of the line before it, so that edited or
+// removed lines break the chain. The file is only created by the first write,
+// and every write holds an exclusive lock on the file, so that provider
+// instances and Terraform runs sharing the file keep a single chain.
+type auditLog struct {
+	path      string
+	workspace string
+
+	// mu guards the hash of the last line and the size of the file after the
+	// last write of this audit log. The hash is reused while the file keeps
+	// that size, i.e. while nothing else has written to it.
+	mu       sync.Mutex
+	lastHash string
+	size     int64
+}
+
+// auditEntry is a line of the audit log.
+type auditEntry struct {
+	Timestamp    string `json:"timestamp"`
+	Operation    string `json:"operation"`
+	AccountID    string `json:"account_id"`
+	ClusterID    string `json:"cluster_id"`
+	Object       string `json:"object"`
+	Request      any    `json:"request,omitempty"`
+	Status       int    `json:"status"`
+	Error        string `json:"error,omitempty"`
+	Workspace    string `json:"workspace"`
+	PreviousHash string `json:"previous_hash"`
+}
+
+// newAuditLog returns an audit log writing to path, or nil when path is
+// empty. It fails when path is a directory or its directory does not exist;
+// the file itself is created by the first write.
+func newAuditLog(path string) (*auditLog, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	info, err := os.Stat(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		if _, err := os.Stat(filepath.Dir(path)); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case info.IsDir():
+		return nil, fmt.Errorf("%s is a directory", path)
+	}
+
+	return &auditLog{
+		path:      path,
+		workspace: terraformWorkspace(),
+		size:      -1,
+	}, nil
+}
+
+// terraformWorkspace returns the name of the selected Terraform workspace.
+func terraformWorkspace() string {
+	if workspace := os.Getenv("TF_WORKSPACE"); workspace != "" {
+		return workspace
+	}
+
+	environment, err := os.ReadFile(".terraform/environment")
+	if err == nil && len(bytes.TrimSpace(environment)) > 0 {
+		return string(bytes.TrimSpace(environment))
+	}
+	return "default"
+}
+
+// record appends the entry to the audit log. Failures are reported as
+// warnings, as the API call has already been made.
+func (l *auditLog) record(entry auditEntry) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if l == nil {
+		return diags
+	}
+
+	if err := l.append(entry); err != nil {
+		diags.AddWarning(
+			"Unable to write audit log",
+			fmt.Sprintf("The %s call was made, but could not be recorded in the audit log %s: %s", entry.Operation, l.path, err.Error()),
+		)
+	}
+	return diags
+}
+
+func (l *auditLog) append(entry auditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	request, err := redactSecrets(entry.Request)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := lockFile(file); err != nil {
+		return err
+	}
+	defer func() { _ = unlockFile(file) }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() != l.size {
+		l.lastHash, err = lastLineHash(file, info.Size())
+		if err != nil {
+			return err
+		}
+	}
+
+	entry.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	entry.Request = request
+	entry.Workspace = l.workspace
+	entry.PreviousHash = l.lastHash
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Forget the cached hash until the line is known to be written in full
+	l.size = -1
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256(line)
+	l.lastHash = hex.EncodeToString(hash[:])
+	l.size = info.Size() + int64(len(line)) + 1
+	return nil
+}
+
+// AUDIT_LOG_READ_CHUNK is the number of bytes read at a time from the end of
+// the audit log when looking for its last line.
+const AUDIT_LOG_READ_CHUNK = 4096
+
+// lastLineHash returns the hex encoded SHA-256 hash of the last line of the
+// file of the given size, or an empty string when there is none. It only
+// reads the file from the end back to the start of the last line.
+func lastLineHash(file *os.File, size int64) (string, error) {
+	var tail []byte
+	for offset := size; offset > 0; {
+		chunkSize := min(offset, AUDIT_LOG_READ_CHUNK)
+		offset -= chunkSize
+
+		chunk := make([]byte, chunkSize)
+		if _, err := file.ReadAt(chunk, offset); err != nil {
+			return "", err
+		}
+		tail = append(chunk, tail...)
+
+		trimmed := bytes.TrimRight(tail, "\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 {
+			tail = trimmed[i+1:]
+			break
+		}
+		if offset == 0 {
+			tail = trimmed
+		}
+	}
+
+	tail = bytes.TrimRight(tail, "\n")
+	if len(tail) == 0 {
+		return "", nil
+	}
+
+	hash := sha256.Sum256(tail)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// redactSecrets returns the JSON representation of value with the values of
+// secret fields replaced.
+func redactSecrets(value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var redacted any
+	if err := json.Unmarshal(content, &redacted); err != nil {
+		return nil, err
+	}
+	return redactValue(redacted), nil
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if isSecretField(key) {
+				v[key] = AUDIT_LOG_REDACTED
+			} else {
+				v[key] = redactValue(field)
+			}
+		}
+	case []any:
+		for i, element := range v {
+			v[i] = redactValue(element)
+		}
+	}
+	return value
+}
+
+func isSecretField(key string) bool {
+	key = strings.ToLower(strings.ReplaceAll(key, "_", ""))
+	return key == "accesstoken" || key == "token" || key == "password" || key == "secret"
+}
+
+// auditStatus returns the HTTP status of a response for the audit log, or 0
+// when the call failed before a response was received.
+func auditStatus(response interface{ StatusCode() int }, err error) int {
+	if err != nil {
+		return 0
+	}
+	return response.StatusCode()
+}
+
+// auditError returns the error of a call for the audit log.
+func auditError(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
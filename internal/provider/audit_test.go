@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestAuditLog(t *testing.T) {
+	t.Setenv("TF_WORKSPACE", "production")
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	log, err := newAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := []auditEntry{
+		{
+			Operation: "create_token",
+			Object:    "signals",
+			Request:   map[string]any{"description": "signals", "access_token": "apiv1_secret"},
+			Status:    200,
+		},
+		{
+			Operation: "delete_token",
+			Object:    "7f7fa77d-b77e-77ba-7777-77cd077d0f7c",
+			Status:    204,
+		},
+	}
+	for _, entry := range entries {
+		if diags := log.record(entry); diags.HasError() || diags.WarningsCount() > 0 {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+	}
+
+	logged := testCheckAuditChain(t, path)
+	if len(logged) != len(entries) {
+		t.Fatalf("expected %d lines, got %d", len(entries), len(logged))
+	}
+	for i, entry := range logged {
+		if entry.Workspace != "production" {
+			t.Errorf("line %d: expected workspace production, got %q", i, entry.Workspace)
+		}
+		if request, ok := entry.Request.(map[string]any); ok && request["access_token"] != AUDIT_LOG_REDACTED {
+			t.Errorf("line %d: expected access_token to be redacted, got %v", i, request["access_token"])
+		}
+	}
+}
+
+func TestAuditLogSharedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	var logs []*auditLog
+	for range 2 {
+		log, err := newAuditLog(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		logs = append(logs, log)
+	}
+	record := func(log *auditLog, description string) {
+		if diags := log.record(auditEntry{Operation: "create_token", Request: map[string]any{"description": description}}); len(diags) > 0 {
+			t.Errorf("unexpected diagnostics: %v", diags)
+		}
+	}
+
+	// Provider instances sharing the file take turns, and some lines are
+	// longer than a read chunk
+	for i := range 10 {
+		for j, log := range logs {
+			description := fmt.Sprintf("signals %d %d", i, j)
+			if i%3 == 0 {
+				description += strings.Repeat("x", 2*AUDIT_LOG_READ_CHUNK)
+			}
+			record(log, description)
+		}
+	}
+
+	// Provider instances sharing the file write concurrently
+	var wg sync.WaitGroup
+	for j, log := range logs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 10 {
+				record(log, fmt.Sprintf("alerts %d %d", i, j))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if logged := testCheckAuditChain(t, path); len(logged) != 40 {
+		t.Errorf("expected 40 lines, got %d", len(logged))
+	}
+}
+
+func TestAuditLogCreatedOnWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.jsonl")
+
+	log, err := newAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected the audit log not to exist before the first write, got %v", err)
+	}
+
+	if diags := log.record(auditEntry{Operation: "delete_database", Object: "signals"}); len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if logged := testCheckAuditChain(t, path); len(logged) != 1 {
+		t.Errorf("expected 1 line, got %d", len(logged))
+	}
+
+	for name, invalidPath := range map[string]string{
+		"directory":         dir,
+		"missing directory": filepath.Join(dir, "missing", "audit.jsonl"),
+	} {
+		if _, err := newAuditLog(invalidPath); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// testCheckAuditChain checks that every line of the audit log carries the hash
+// of the line before it, and returns the entries.
+func testCheckAuditChain(t *testing.T, path string) []auditEntry {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []auditEntry
+	previousHash := ""
+	for i, line := range bytes.Split(bytes.TrimRight(content, "\n"), []byte("\n")) {
+		var entry auditEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatal(err)
+		}
+
+		if entry.PreviousHash != previousHash {
+			t.Errorf("line %d: expected previous hash %q, got %q", i, previousHash, entry.PreviousHash)
+		}
+
+		hash := sha256.Sum256(line)
+		previousHash = hex.EncodeToString(hash[:])
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAuditLogDisabled(t *testing.T) {
+	log, err := newAuditLog("")
+	if err != nil || log != nil {
+		t.Fatalf("expected no audit log, got %v, %v", log, err)
+	}
+
+	if diags := log.record(auditEntry{Operation: "create_database"}); len(diags) > 0 {
+		t.Errorf("unexpected diagnostics: %v", diags)
+	}
+}
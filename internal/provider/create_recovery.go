@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/thulasirajkomminar/influxdb3-management-go"
+)
+
+const (
+	// DATABASE_READY_TIMEOUT is how long a database create waits for the database to be listed.
+	DATABASE_READY_TIMEOUT = 2 * time.Minute
+	// DATABASE_READY_INTERVAL is the time between listings while waiting for a database.
+	DATABASE_READY_INTERVAL = 2 * time.Second
+	// TOKEN_CREATE_DATABASE_RETRIES is how often a token create is retried when a database of its permissions is not found.
+	TOKEN_CREATE_DATABASE_RETRIES = 5
+	// TOKEN_CREATE_DATABASE_RETRY_INTERVAL is the time between those retries.
+	TOKEN_CREATE_DATABASE_RETRY_INTERVAL = 3 * time.Second
+)
+
+type requestAttemptsKey struct{}
+
+// requestAttempts counts the attempts the retrying client makes for a request.
+type requestAttempts struct {
+	count atomic.Int32
+}
+
+// withRequestAttempts returns a context counting the attempts of the request
+// made with it.
+func withRequestAttempts(ctx context.Context) (context.Context, *requestAttempts) {
+	attempts := &requestAttempts{}
+	return context.WithValue(ctx, requestAttemptsKey{}, attempts), attempts
+}
+
+// countRequestAttempt is the request hook of the retrying client. It records
+// the attempts of requests made with withRequestAttempts.
+func countRequestAttempt(_ retryablehttp.Logger, req *http.Request, retry int) {
+	if attempts, ok := req.Context().Value(requestAttemptsKey{}).(*requestAttempts); ok {
+		attempts.count.Store(int32(retry + 1))
+	}
+}
+
+// ambiguousCreate reports whether a failed create request may nevertheless
+// have created the object: no response was received, the cluster failed, or a
+// retry conflicted with the object an earlier attempt created.
+func ambiguousCreate(attempts *requestAttempts, status int, err error) bool {
+	switch {
+	case err != nil:
+		return true
+	case status >= 500:
+		return true
+	case status == http.StatusConflict:
+		return attempts.count.Load() > 1
+	}
+	return false
+}
+
+// databaseNotFound reports whether the bad request error of a rejected token
+// create reports one of the databases of its permissions as not found. Only the
+// message of the decoded error is inspected, and only for the given database
+// names, so that other validation errors are not retried.
+func databaseNotFound(badRequest *influxdb3.Error, databases []string) bool {
+	if badRequest == nil {
+		return false
+	}
+
+	message := strings.ToLower(badRequest.Message)
+	if !strings.Contains(message, "not found") && !strings.Contains(message, "does not exist") {
+		return false
+	}
+	for _, database := range databases {
+		if strings.Contains(message, strings.ToLower(database)) {
+			return true
+		}
+	}
+	return false
+}
+
+// sleepContext waits for the duration, and reports false when the context is
+// done first.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/thulasirajkomminar/influxdb3-management-go"
+)
+
+func TestAmbiguousCreate(t *testing.T) {
+	testCases := map[string]struct {
+		attempts int32
+		status   int
+		err      error
+		expected bool
+	}{
+		"error": {
+			attempts: 4,
+			err:      errors.New("giving up after 4 attempt(s)"),
+			expected: true,
+		},
+		"server error": {
+			attempts: 1,
+			status:   http.StatusBadGateway,
+			expected: true,
+		},
+		"conflict": {
+			attempts: 1,
+			status:   http.StatusConflict,
+			expected: false,
+		},
+		"retried conflict": {
+			attempts: 2,
+			status:   http.StatusConflict,
+			expected: true,
+		},
+		"bad request": {
+			attempts: 2,
+			status:   http.StatusBadRequest,
+			expected: false,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			attempts := &requestAttempts{}
+			attempts.count.Store(testCase.attempts)
+			if got := ambiguousCreate(attempts, testCase.status, testCase.err); got != testCase.expected {
+				t.Errorf("expected %t, got %t", testCase.expected, got)
+			}
+		})
+	}
+}
+
+func TestCountRequestAttempt(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer server.Close()
+
+	retryClient := retryablehttp.NewClient()
+	retryClient.Logger = nil
+	retryClient.RetryWaitMin = time.Millisecond
+	retryClient.RetryWaitMax = time.Millisecond
+	retryClient.RequestLogHook = countRequestAttempt
+
+	ctx, attempts := withRequestAttempts(t.Context())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := retryClient.StandardClient().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if got := attempts.count.Load(); got != 2 {
+		t.Errorf("expected 2 attempts, got %d", got)
+	}
+	if !ambiguousCreate(attempts, resp.StatusCode, nil) {
+		t.Error("expected a conflict after a retry to be ambiguous")
+	}
+}
+
+func TestDatabaseNotFound(t *testing.T) {
+	testCases := map[string]struct {
+		status   int
+		body     string
+		expected bool
+	}{
+		"not found": {
+			status:   http.StatusBadRequest,
+			body:     `{"code":400,"message":"database \"prod_signals\" not found"}`,
+			expected: true,
+		},
+		"does not exist": {
+			status:   http.StatusBadRequest,
+			body:     `{"code":400,"message":"Database prod_signals does not exist"}`,
+			expected: true,
+		},
+		"other database": {
+			status:   http.StatusBadRequest,
+			body:     `{"code":400,"message":"database \"prod_metrics\" not found"}`,
+			expected: false,
+		},
+		"other validation error": {
+			status:   http.StatusBadRequest,
+			body:     `{"code":400,"message":"invalid action for database prod_signals"}`,
+			expected: false,
+		},
+		"not a bad request": {
+			status:   http.StatusInternalServerError,
+			body:     `{"code":500,"message":"database \"prod_signals\" not found"}`,
+			expected: false,
+		},
+		"unstructured": {
+			status:   http.StatusBadRequest,
+			body:     `database "prod_signals" not found`,
+			expected: false,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.intercept = func(w http.ResponseWriter, r *http.Request, resource string) bool {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(testCase.status)
+				_, _ = w.Write([]byte(testCase.body))
+				return false
+			}
+
+			resp, err := api.client.CreateDatabaseTokenWithResponse(t.Context(), api.accountID, api.clusterID, influxdb3.CreateDatabaseTokenJSONRequestBody{
+				Description: "signals",
+			})
+			if err != nil && testCase.expected {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var badRequest *influxdb3.Error
+			if resp != nil {
+				badRequest = resp.JSON400
+			}
+			if got := databaseNotFound(badRequest, []string{"prod_signals"}); got != testCase.expected {
+				t.Errorf("expected %t, got %t", testCase.expected, got)
+			}
+		})
+	}
+}
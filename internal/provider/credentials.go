@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/thulasirajkomminar/influxdb3-management-go"
+)
+
+// validateCredentials checks that the management token can list the databases
+// of the configured cluster, so that a wrong token, account or cluster fails
+// once in Configure rather than in every resource and data source. The
+// listing is kept in the database cache for the first refresh.
+func validateCredentials(ctx context.Context, cache *databaseCache, accountID influxdb3.UuidV4, clusterID influxdb3.UuidV4) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	readDatabasesResponse, err := cache.list(ctx)
+	if err != nil {
+		diags.AddError(
+			"Unable to Verify InfluxDB V3 Credentials",
+			"The provider could not reach the InfluxDB V3 management API to verify the credentials. "+
+				"Set skip_credentials_validation to true to skip this check.\n\n"+
+				"Error: "+err.Error(),
+		)
+		return diags
+	}
+
+	if readDatabasesResponse.StatusCode() == 200 {
+		return diags
+	}
+
+	errMsg, err := formatErrorResponse(readDatabasesResponse, readDatabasesResponse.StatusCode())
+	if err != nil {
+		errMsg = fmt.Sprintf("HTTP Status Code: %d", readDatabasesResponse.StatusCode())
+	}
+	diags.Append(credentialsDiagnostic(readDatabasesResponse.StatusCode(), errMsg, accountID, clusterID))
+	return diags
+}
+
+// credentialsDiagnostic describes why the management token could not list the
+// databases of the cluster, based on the status code of the listing.
+func credentialsDiagnostic(statusCode int, errMsg string, accountID influxdb3.UuidV4, clusterID influxdb3.UuidV4) diag.Diagnostic {
+	switch statusCode {
+	case 401:
+		return diag.NewAttributeErrorDiagnostic(
+			path.Root("token"),
+			"Invalid InfluxDB V3 Management Token",
+			"The InfluxDB V3 management API rejected the management token. "+
+				"The token may be mistyped, expired or revoked. "+
+				"Set the token value in the configuration or use the INFLUXDB3_TOKEN environment variable.\n\n"+
+				errMsg,
+		)
+	case 403:
+		return diag.NewAttributeErrorDiagnostic(
+			path.Root("token"),
+			"Insufficient InfluxDB V3 Management Token Scope",
+			fmt.Sprintf("The management token is valid, but it is not allowed to list the databases of cluster %s in account %s. ", clusterID, accountID)+
+				"Ensure the token was created for this account and grants access to the cluster.\n\n"+
+				errMsg,
+		)
+	case 404:
+		return diag.NewAttributeErrorDiagnostic(
+			path.Root("cluster_id"),
+			"InfluxDB V3 Cluster Not Found",
+			fmt.Sprintf("Cluster %s was not found in account %s. ", clusterID, accountID)+
+				"Check that the account_id and cluster_id values, or the INFLUXDB3_ACCOUNT_ID and INFLUXDB3_CLUSTER_ID environment variables, refer to the same account.\n\n"+
+				errMsg,
+		)
+	default:
+		return diag.NewErrorDiagnostic(
+			"Unable to Verify InfluxDB V3 Credentials",
+			"The InfluxDB V3 management API returned an unexpected response while verifying the credentials. "+
+				"Set skip_credentials_validation to true to skip this check.\n\n"+
+				errMsg,
+		)
+	}
+}
@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/thulasirajkomminar/influxdb3-management-go"
+)
+
+func TestValidateCredentials(t *testing.T) {
+	accountID := uuid.New()
+	clusterID := uuid.New()
+
+	testCases := map[string]struct {
+		statusCode    int
+		err           error
+		expectedError string
+		expectedPath  path.Path
+	}{
+		"valid": {
+			statusCode: http.StatusOK,
+		},
+		"invalid token": {
+			statusCode:    http.StatusUnauthorized,
+			expectedError: "Invalid InfluxDB V3 Management Token",
+			expectedPath:  path.Root("token"),
+		},
+		"insufficient scope": {
+			statusCode:    http.StatusForbidden,
+			expectedError: "Insufficient InfluxDB V3 Management Token Scope",
+			expectedPath:  path.Root("token"),
+		},
+		"cluster not in account": {
+			statusCode:    http.StatusNotFound,
+			expectedError: "InfluxDB V3 Cluster Not Found",
+			expectedPath:  path.Root("cluster_id"),
+		},
+		"unexpected status": {
+			statusCode:    http.StatusInternalServerError,
+			expectedError: "Unable to Verify InfluxDB V3 Credentials",
+		},
+		"request error": {
+			err:           errors.New("connection refused"),
+			expectedError: "Unable to Verify InfluxDB V3 Credentials",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := &databaseCache{
+				listDatabases: func(ctx context.Context) (*influxdb3.GetClusterDatabasesResponse, error) {
+					if testCase.err != nil {
+						return nil, testCase.err
+					}
+					return &influxdb3.GetClusterDatabasesResponse{HTTPResponse: &http.Response{StatusCode: testCase.statusCode}}, nil
+				},
+			}
+
+			diags := validateCredentials(t.Context(), cache, accountID, clusterID)
+			if testCase.expectedError == "" {
+				if diags.HasError() {
+					t.Fatalf("unexpected error: %v", diags)
+				}
+				return
+			}
+
+			if diags.ErrorsCount() != 1 {
+				t.Fatalf("expected 1 error, got %v", diags)
+			}
+			if summary := diags.Errors()[0].Summary(); summary != testCase.expectedError {
+				t.Errorf("expected %q, got %q", testCase.expectedError, summary)
+			}
+			if len(testCase.expectedPath.Steps()) == 0 {
+				return
+			}
+			withPath, ok := diags.Errors()[0].(diag.DiagnosticWithPath)
+			if !ok || !withPath.Path().Equal(testCase.expectedPath) {
+				t.Errorf("expected an error on %s, got %v", testCase.expectedPath, diags.Errors()[0])
+			}
+		})
+	}
+}
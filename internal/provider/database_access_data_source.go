@@ -0,0 +1,280 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/thulasirajkomminar/influxdb3-management-go"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &DatabaseAccessDataSource{}
+	_ datasource.DataSourceWithConfigure = &DatabaseAccessDataSource{}
+)
+
+// NewDatabaseAccessDataSource is a helper function to simplify the provider implementation.
+func NewDatabaseAccessDataSource() datasource.DataSource {
+	return &DatabaseAccessDataSource{}
+}
+
+// DatabaseAccessDataSource is the data source implementation.
+type DatabaseAccessDataSource struct {
+	accountID     influxdb3.UuidV4
+	client        influxdb3.ClientWithResponses
+	clusterID     influxdb3.UuidV4
+	databaseCache *databaseCache
+	namespace     databaseNamespace
+}
+
+// DatabaseAccessDataSourceModel describes the data source data model.
+type DatabaseAccessDataSourceModel struct {
+	Database types.String               `tfsdk:"database"`
+	Tokens   []DatabaseAccessTokenModel `tfsdk:"tokens"`
+}
+
+// DatabaseAccessTokenModel maps a database token with access to a database.
+type DatabaseAccessTokenModel struct {
+	Actions     []types.String `tfsdk:"actions"`
+	Description types.String   `tfsdk:"description"`
+	ExpiresAt   types.String   `tfsdk:"expires_at"`
+	Id          types.String   `tfsdk:"id"`
+	IsExpired   types.Bool     `tfsdk:"is_expired"`
+	Wildcard    types.Bool     `tfsdk:"wildcard"`
+}
+
+// Metadata returns the data source type name.
+func (d *DatabaseAccessDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_database_access"
+}
+
+// Schema defines the schema for the data source.
+func (d *DatabaseAccessDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Gets the database tokens that can access a cluster database, either through a permission on the database or on all databases (`*`).",
+
+		Attributes: map[string]schema.Attribute{
+			"database": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the cluster database, without the `database_name_prefix` of the provider. Together with the prefix, the name must be at most 64 characters.",
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, DATABASE_NAME_MAX_LENGTH),
+				},
+			},
+			"tokens": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The database tokens that can access the cluster database.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"actions": schema.ListAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+							Description: "The actions the database token allows on the cluster database.",
+						},
+						"description": schema.StringAttribute{
+							Computed:    true,
+							Description: "The description of the database token.",
+						},
+						"expires_at": schema.StringAttribute{
+							Computed:    true,
+							Description: "The date and time that the database token expires, if applicable. Uses RFC3339 format.",
+						},
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "The ID of the database token.",
+						},
+						"is_expired": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the database token has expired.",
+						},
+						"wildcard": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether any of the actions are allowed through a permission on all databases (`*`).",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *DatabaseAccessDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	pd, ok := req.ProviderData.(providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected influxdb3.ClientWithResponses, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.accountID = pd.accountID
+	d.client = pd.client
+	d.clusterID = pd.clusterID
+	d.databaseCache = pd.databaseCache
+	d.namespace = pd.namespace
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *DatabaseAccessDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state DatabaseAccessDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The prefix is only known once the provider is configured
+	resp.Diagnostics.Append(d.namespace.validateNameLength(path.Root("database"), state.Database)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readDatabasesResponse, err := d.databaseCache.list(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting database",
+			err.Error(),
+		)
+		return
+	}
+
+	if readDatabasesResponse.StatusCode() != 200 {
+		errMsg, err := formatErrorResponse(readDatabasesResponse, readDatabasesResponse.StatusCode())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error formatting error response",
+				err.Error(),
+			)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error getting database",
+			errMsg,
+		)
+		return
+	}
+
+	// Check if the database exists
+	readDatabase, err := getDatabaseByName(*readDatabasesResponse, d.namespace.qualify(state.Database.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting database",
+			"Unexpected error: "+err.Error(),
+		)
+		return
+	}
+	if readDatabase == nil {
+		resp.Diagnostics.AddError(
+			"Database not found",
+			fmt.Sprintf("Database with name %s not found", state.Database.ValueString()),
+		)
+		return
+	}
+
+	readTokensResponse, err := d.client.GetDatabaseTokensWithResponse(ctx, d.accountID, d.clusterID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting tokens",
+			err.Error(),
+		)
+		return
+	}
+
+	if readTokensResponse.StatusCode() != 200 {
+		errMsg, err := formatErrorResponse(readTokensResponse, readTokensResponse.StatusCode())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error formatting error response",
+				err.Error(),
+			)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error getting tokens",
+			errMsg,
+		)
+		return
+	}
+
+	// Map response body to model, leaving out database tokens with permissions
+	// outside the namespace as the tokens data source does
+	state.Tokens = []DatabaseAccessTokenModel{}
+	for _, token := range *readTokensResponse.JSON200 {
+		permissions, ok := d.namespace.unqualifyPermissions(getPermissions(token.Permissions))
+		if !ok {
+			continue
+		}
+
+		tokenState := TokenModel{
+			Description: types.StringValue(token.Description),
+			Id:          types.StringValue(token.Id.String()),
+			Permissions: permissions,
+		}
+		tokenState.setExpiry(token.ExpiresAt)
+
+		tokenAccess, ok := getDatabaseAccess(tokenState, state.Database.ValueString())
+		if ok {
+			state.Tokens = append(state.Tokens, tokenAccess)
+		}
+	}
+
+	// Set state
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// getDatabaseAccess returns the access the token has on the database, if any.
+func getDatabaseAccess(token TokenModel, database string) (DatabaseAccessTokenModel, bool) {
+	tokenAccess := DatabaseAccessTokenModel{
+		Actions:     []types.String{},
+		Description: token.Description,
+		ExpiresAt:   token.ExpiresAt,
+		Id:          token.Id,
+		IsExpired:   token.IsExpired,
+		Wildcard:    types.BoolValue(false),
+	}
+
+	actions := map[string]bool{}
+	for _, permission := range token.Permissions {
+		resource := permission.Resource.ValueString()
+		if resource != database && resource != "*" {
+			continue
+		}
+		if resource == "*" {
+			tokenAccess.Wildcard = types.BoolValue(true)
+		}
+		actions[permission.Action.ValueString()] = true
+	}
+
+	if len(actions) == 0 {
+		return tokenAccess, false
+	}
+
+	sortedActions := make([]string, 0, len(actions))
+	for action := range actions {
+		sortedActions = append(sortedActions, action)
+	}
+	sort.Strings(sortedActions)
+	for _, action := range sortedActions {
+		tokenAccess.Actions = append(tokenAccess.Actions, types.StringValue(action))
+	}
+	return tokenAccess, true
+}
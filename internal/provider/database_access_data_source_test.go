@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDatabaseAccessDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + testAccDatabaseAccessDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.influxdb3_database_access.test", "tokens.#", "1"),
+					resource.TestCheckResourceAttr("data.influxdb3_database_access.test", "tokens.0.description", "Access access_test database"),
+					resource.TestCheckResourceAttr("data.influxdb3_database_access.test", "tokens.0.actions.#", "2"),
+					resource.TestCheckResourceAttr("data.influxdb3_database_access.test", "tokens.0.wildcard", "false"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDatabaseAccessDataSourceConfig = `
+resource "influxdb3_database" "test" {
+  name = "access_test"
+}
+
+resource "influxdb3_token" "test" {
+  description     = "Access access_test database"
+  read_databases  = [influxdb3_database.test.name]
+  write_databases = [influxdb3_database.test.name]
+}
+
+data "influxdb3_database_access" "test" {
+  database = one(influxdb3_token.test.read_databases)
+}
+`
+
+func TestGetDatabaseAccess(t *testing.T) {
+	permission := func(action string, resource string) TokenPermissionModel {
+		return TokenPermissionModel{Action: types.StringValue(action), Resource: types.StringValue(resource)}
+	}
+
+	testCases := map[string]struct {
+		permissions      []TokenPermissionModel
+		expectedOk       bool
+		expectedActions  []string
+		expectedWildcard bool
+	}{
+		"explicit": {
+			permissions:     []TokenPermissionModel{permission("read", "signals"), permission("write", "signals")},
+			expectedOk:      true,
+			expectedActions: []string{"read", "write"},
+		},
+		"wildcard": {
+			permissions:      []TokenPermissionModel{permission("read", "*"), permission("read", "signals")},
+			expectedOk:       true,
+			expectedActions:  []string{"read"},
+			expectedWildcard: true,
+		},
+		"other-database": {
+			permissions: []TokenPermissionModel{permission("write", "telemetry")},
+			expectedOk:  false,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			token := TokenModel{Id: types.StringValue("7f7fa77d-b77e-77ba-7777-77cd077d0f7c"), Permissions: testCase.permissions}
+
+			got, ok := getDatabaseAccess(token, "signals")
+			if ok != testCase.expectedOk {
+				t.Fatalf("expected ok %t, got %t", testCase.expectedOk, ok)
+			}
+			if !ok {
+				return
+			}
+
+			var actions []string
+			for _, action := range got.Actions {
+				actions = append(actions, action.ValueString())
+			}
+			if len(actions) != len(testCase.expectedActions) {
+				t.Fatalf("expected actions %v, got %v", testCase.expectedActions, actions)
+			}
+			for i := range actions {
+				if actions[i] != testCase.expectedActions[i] {
+					t.Fatalf("expected actions %v, got %v", testCase.expectedActions, actions)
+				}
+			}
+			if got.Wildcard.ValueBool() != testCase.expectedWildcard {
+				t.Errorf("expected wildcard %t, got %t", testCase.expectedWildcard, got.Wildcard.ValueBool())
+			}
+		})
+	}
+}
+
+func TestDatabaseAccessDataSourceReadNamespace(t *testing.T) {
+	testCases := map[string]struct {
+		database       string
+		expectedTokens []string
+		expectedError  string
+	}{
+		"database": {
+			database:       "signals",
+			expectedTokens: []string{"team"},
+		},
+		"name too long with the prefix": {
+			database:      strings.Repeat("s", 60),
+			expectedError: "Invalid Attribute Value Length",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.addDatabase(map[string]any{"name": "team-signals"})
+			descriptions := map[string]string{}
+			for description, resources := range map[string][]string{
+				"team":          {"team-signals"},
+				"all databases": {"*"},
+				"other team":    {"team-signals", "ops-signals"},
+			} {
+				var permissions []map[string]any
+				for _, resource := range resources {
+					permissions = append(permissions, map[string]any{"action": "read", "resource": resource})
+				}
+				descriptions[api.addToken(map[string]any{"description": description, "permissions": permissions})] = description
+			}
+
+			d := &DatabaseAccessDataSource{
+				accountID:     api.accountID,
+				client:        api.client,
+				clusterID:     api.clusterID,
+				databaseCache: newDatabaseCache(api.client, api.accountID, api.clusterID),
+				namespace:     "team-",
+			}
+			schemaResp := &datasource.SchemaResponse{}
+			d.Schema(t.Context(), datasource.SchemaRequest{}, schemaResp)
+			objectType, ok := schemaResp.Schema.Type().TerraformType(t.Context()).(tftypes.Object)
+			if !ok {
+				t.Fatalf("expected an object schema, got %s", schemaResp.Schema.Type())
+			}
+			config := map[string]tftypes.Value{}
+			for name, attributeType := range objectType.AttributeTypes {
+				config[name] = tftypes.NewValue(attributeType, nil)
+			}
+			config["database"] = tftypes.NewValue(tftypes.String, testCase.database)
+
+			req := datasource.ReadRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, config)}}
+			resp := &datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)}}
+			d.Read(t.Context(), req, resp)
+			testCheckDiagnosticSummaries(t, resp.Diagnostics, testCase.expectedError, "")
+			if testCase.expectedError != "" {
+				return
+			}
+
+			var state DatabaseAccessDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(t.Context(), &state)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			var got []string
+			for _, token := range state.Tokens {
+				got = append(got, descriptions[token.Id.ValueString()])
+			}
+			if !slices.Equal(got, testCase.expectedTokens) {
+				t.Errorf("expected tokens %v, got %v", testCase.expectedTokens, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"github.com/thulasirajkomminar/influxdb3-management-go"
+)
+
+// databaseCache caches the database listing of the cluster of a provider
+// instance, so that refreshing many databases lists them once. Concurrent
+// callers share a single request, and database mutations invalidate the
+// cached listing.
+type databaseCache struct {
+	listDatabases func(ctx context.Context) (*influxdb3.GetClusterDatabasesResponse, error)
+
+	mu         sync.Mutex
+	call       *databaseCacheCall
+	generation uint64
+	response   *influxdb3.GetClusterDatabasesResponse
+}
+
+// databaseCacheCall is a listing request shared by concurrent callers.
+type databaseCacheCall struct {
+	done     chan struct{}
+	response *influxdb3.GetClusterDatabasesResponse
+	err      error
+}
+
+func newDatabaseCache(client influxdb3.ClientWithResponses, accountID influxdb3.UuidV4, clusterID influxdb3.UuidV4) *databaseCache {
+	return &databaseCache{
+		listDatabases: func(ctx context.Context) (*influxdb3.GetClusterDatabasesResponse, error) {
+			return client.GetClusterDatabasesWithResponse(ctx, accountID, clusterID)
+		},
+	}
+}
+
+// list returns the databases of the cluster. Only successful listings are
+// cached; callers check the status code as for an uncached response.
+func (c *databaseCache) list(ctx context.Context) (*influxdb3.GetClusterDatabasesResponse, error) {
+	c.mu.Lock()
+	if c.response != nil {
+		response := c.response
+		c.mu.Unlock()
+		return response, nil
+	}
+
+	call := c.call
+	if call == nil {
+		call = &databaseCacheCall{done: make(chan struct{})}
+		c.call = call
+		go c.fetch(context.WithoutCancel(ctx), call, c.generation)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.response, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *databaseCache) fetch(ctx context.Context, call *databaseCacheCall, generation uint64) {
+	call.response, call.err = c.listDatabases(ctx)
+
+	c.mu.Lock()
+	// Listings started before an invalidation may already be stale
+	if c.generation == generation {
+		if call.err == nil && call.response.StatusCode() == 200 {
+			c.response = call.response
+		}
+		c.call = nil
+	}
+	c.mu.Unlock()
+
+	close(call.done)
+}
+
+// invalidate drops the cached listing. Callers after it wait for a new
+// listing, rather than joining one already in flight.
+func (c *databaseCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.call = nil
+	c.response = nil
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/thulasirajkomminar/influxdb3-management-go"
+)
+
+func TestDatabaseCache(t *testing.T) {
+	var requests atomic.Int32
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	statusCode := http.StatusOK
+	cache := &databaseCache{
+		listDatabases: func(ctx context.Context) (*influxdb3.GetClusterDatabasesResponse, error) {
+			requests.Add(1)
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-release
+			return &influxdb3.GetClusterDatabasesResponse{HTTPResponse: &http.Response{StatusCode: statusCode}}, nil
+		},
+	}
+
+	// Concurrent callers share a single listing
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.list(t.Context()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	<-started
+	close(release)
+	wg.Wait()
+
+	if _, err := cache.list(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("expected 1 listing, got %d", got)
+	}
+
+	// Mutations invalidate the cached listing
+	cache.invalidate()
+	statusCode = http.StatusInternalServerError
+	if _, err := cache.list(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("expected 2 listings, got %d", got)
+	}
+
+	// Failed listings are not cached
+	statusCode = http.StatusOK
+	response, err := cache.list(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode() != http.StatusOK {
+		t.Errorf("expected status 200, got %d", response.StatusCode())
+	}
+	if got := requests.Load(); got != 3 {
+		t.Fatalf("expected 3 listings, got %d", got)
+	}
+}
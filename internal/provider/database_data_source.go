@@ -22,9 +22,11 @@ func NewDatabaseDataSource() datasource.DataSource {
 
 // DatabasesDataSource is the data source implementation.
 type DatabaseDataSource struct {
-	accountID influxdb3.UuidV4
-	client    influxdb3.ClientWithResponses
-	clusterID influxdb3.UuidV4
+	accountID     influxdb3.UuidV4
+	client        influxdb3.ClientWithResponses
+	clusterID     influxdb3.UuidV4
+	databaseCache *databaseCache
+	namespace     databaseNamespace
 }
 
 // Metadata returns the data source type name.
@@ -102,6 +104,8 @@ func (d *DatabaseDataSource) Configure(ctx context.Context, req datasource.Confi
 	d.accountID = pd.accountID
 	d.client = pd.client
 	d.clusterID = pd.clusterID
+	d.databaseCache = pd.databaseCache
+	d.namespace = pd.namespace
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -122,7 +126,7 @@ func (d *DatabaseDataSource) Read(ctx context.Context, req datasource.ReadReques
 		return
 	}
 
-	readDatabasesResponse, err := d.client.GetClusterDatabasesWithResponse(ctx, d.accountID, d.clusterID)
+	readDatabasesResponse, err := d.databaseCache.list(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error getting database",
@@ -148,7 +152,7 @@ func (d *DatabaseDataSource) Read(ctx context.Context, req datasource.ReadReques
 	}
 
 	// Check if the database exists
-	readDatabase, err := getDatabaseByName(*readDatabasesResponse, databaseName.ValueString())
+	readDatabase, err := getDatabaseByName(*readDatabasesResponse, d.namespace.qualify(databaseName.ValueString()))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error getting database",
@@ -165,6 +169,7 @@ func (d *DatabaseDataSource) Read(ctx context.Context, req datasource.ReadReques
 	}
 
 	// Map response body to model
+	readDatabase.Name = databaseName
 	state = *readDatabase
 
 	// Set state
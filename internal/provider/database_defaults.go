@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"math"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	// DATABASE_DEFAULT_MAX_TABLES is the max_tables of databases when neither the resource nor the provider sets it.
+	DATABASE_DEFAULT_MAX_TABLES = 500
+	// DATABASE_DEFAULT_MAX_COLUMNS_PER_TABLE is the max_columns_per_table of databases when neither the resource nor the provider sets it.
+	DATABASE_DEFAULT_MAX_COLUMNS_PER_TABLE = 200
+	// DATABASE_DEFAULT_RETENTION_PERIOD is the retention_period of databases when neither the resource nor the provider sets it.
+	DATABASE_DEFAULT_RETENTION_PERIOD = 0
+)
+
+// DatabaseDefaultsModel maps the provider database_defaults block schema data.
+type DatabaseDefaultsModel struct {
+	MaxColumnsPerTable types.Int64 `tfsdk:"max_columns_per_table"`
+	MaxTables          types.Int64 `tfsdk:"max_tables"`
+	PartitionTemplate  types.List  `tfsdk:"partition_template"`
+	RetentionPeriod    types.Int64 `tfsdk:"retention_period"`
+}
+
+// databaseLimitValidators returns the validators of max_tables and
+// max_columns_per_table. Cloud Dedicated documents no fixed upper limits, as
+// they depend on the cluster, so the upper bound is the 32-bit range the
+// management API takes the limits in. The policy block caps them further.
+func databaseLimitValidators() []validator.Int64 {
+	return []validator.Int64{
+		int64validator.AtLeast(1),
+		int64validator.AtMost(math.MaxInt32),
+	}
+}
+
+// databaseDefaultsBlock returns the schema of the provider database_defaults block.
+func databaseDefaultsBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "Settings for `influxdb3_database` resources that leave them unset. They only apply when a database is created.",
+		Attributes: map[string]schema.Attribute{
+			"max_columns_per_table": schema.Int64Attribute{
+				Description: "The default maximum number of columns per table of databases. The default is `200`.",
+				Optional:    true,
+				Validators:  databaseLimitValidators(),
+			},
+			"max_tables": schema.Int64Attribute{
+				Description: "The default maximum number of tables of databases. The default is `500`.",
+				Optional:    true,
+				Validators:  databaseLimitValidators(),
+			},
+			"partition_template": schema.ListNestedAttribute{
+				Description: "The default partition template of databases. It only applies when a database is created.",
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.UniqueValues(),
+					listvalidator.SizeBetween(1, 8),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							Required:    true,
+							Description: "The type of template part. Valid values are `bucket`, `tag` or `time`.",
+							Validators: []validator.String{
+								stringvalidator.OneOf([]string{"bucket", "tag", "time"}...),
+							},
+						},
+						"value": schema.StringAttribute{
+							Required:    true,
+							Description: "The value of template part. **Note:** For `bucket` partition template type use `jsonencode()` function to encode the value to a string.",
+						},
+					},
+				},
+			},
+			"retention_period": schema.Int64Attribute{
+				Description: "The default retention period of databases in nanoseconds. The default is `0`, which is infinite retention.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+		},
+	}
+}
+
+// databaseDefaults holds the settings of databases that leave them unset.
+type databaseDefaults struct {
+	maxColumnsPerTable int64
+	maxTables          int64
+	partitionTemplate  types.List
+	retentionPeriod    int64
+}
+
+// newDatabaseDefaults merges the database_defaults block of the provider
+// configuration with the built-in defaults. m may be nil.
+func newDatabaseDefaults(m *DatabaseDefaultsModel) *databaseDefaults {
+	defaults := &databaseDefaults{
+		maxColumnsPerTable: DATABASE_DEFAULT_MAX_COLUMNS_PER_TABLE,
+		maxTables:          DATABASE_DEFAULT_MAX_TABLES,
+		partitionTemplate:  partitionTemplateValue(nil),
+		retentionPeriod:    DATABASE_DEFAULT_RETENTION_PERIOD,
+	}
+	if m == nil {
+		return defaults
+	}
+
+	if !m.MaxColumnsPerTable.IsNull() {
+		defaults.maxColumnsPerTable = m.MaxColumnsPerTable.ValueInt64()
+	}
+	if !m.MaxTables.IsNull() {
+		defaults.maxTables = m.MaxTables.ValueInt64()
+	}
+	if !m.RetentionPeriod.IsNull() {
+		defaults.retentionPeriod = m.RetentionPeriod.ValueInt64()
+	}
+	if !m.PartitionTemplate.IsNull() {
+		defaults.partitionTemplate = m.PartitionTemplate
+	}
+
+	return defaults
+}
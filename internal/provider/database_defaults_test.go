@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestNewDatabaseDefaults(t *testing.T) {
+	defaults := newDatabaseDefaults(nil)
+	if defaults.maxTables != 500 || defaults.maxColumnsPerTable != 200 || defaults.retentionPeriod != 0 || !defaults.partitionTemplate.IsNull() {
+		t.Errorf("unexpected built-in defaults: %+v", defaults)
+	}
+
+	defaults = newDatabaseDefaults(&DatabaseDefaultsModel{
+		MaxTables:          types.Int64Value(1000),
+		MaxColumnsPerTable: types.Int64Null(),
+		RetentionPeriod:    types.Int64Value(604800000000000),
+		PartitionTemplate: partitionTemplateValue([]DatabasePartitionTemplateModel{
+			{Type: types.StringValue("tag"), Value: types.StringValue("line")},
+		}),
+	})
+	if defaults.maxTables != 1000 {
+		t.Errorf("expected max_tables 1000, got %d", defaults.maxTables)
+	}
+	if defaults.maxColumnsPerTable != 200 {
+		t.Errorf("expected max_columns_per_table 200, got %d", defaults.maxColumnsPerTable)
+	}
+	if defaults.retentionPeriod != 604800000000000 {
+		t.Errorf("expected retention_period 604800000000000, got %d", defaults.retentionPeriod)
+	}
+	if len(defaults.partitionTemplate.Elements()) != 1 {
+		t.Errorf("expected 1 partition template part, got %d", len(defaults.partitionTemplate.Elements()))
+	}
+}
+
+func TestDatabaseLimitValidators(t *testing.T) {
+	testCases := map[string]struct {
+		value         int64
+		expectedError bool
+	}{
+		"zero":                {value: 0, expectedError: true},
+		"lower bound":         {value: 1},
+		"upper bound":         {value: math.MaxInt32},
+		"beyond 32-bit range": {value: math.MaxInt32 + 1, expectedError: true},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := validator.Int64Request{Path: path.Root("max_tables"), ConfigValue: types.Int64Value(testCase.value)}
+			resp := &validator.Int64Response{}
+			for _, v := range databaseLimitValidators() {
+				v.ValidateInt64(t.Context(), req, resp)
+			}
+			if resp.Diagnostics.HasError() != testCase.expectedError {
+				t.Errorf("expected error: %t, got %v", testCase.expectedError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestDatabaseResourceModifyPlanDefaults(t *testing.T) {
+	existing := map[string]tftypes.Value{
+		"name":                  tftypes.NewValue(tftypes.String, "signals"),
+		"max_tables":            tftypes.NewValue(tftypes.Number, 500),
+		"max_columns_per_table": tftypes.NewValue(tftypes.Number, 200),
+		"retention_period":      tftypes.NewValue(tftypes.Number, 0),
+	}
+
+	testCases := map[string]struct {
+		config             map[string]tftypes.Value
+		state              map[string]tftypes.Value
+		expectedMaxTables  int64
+		expectedRetention  int64
+		expectedPartitions int
+	}{
+		"create": {
+			config:             map[string]tftypes.Value{"name": tftypes.NewValue(tftypes.String, "signals")},
+			expectedMaxTables:  1000,
+			expectedRetention:  604800000000000,
+			expectedPartitions: 1,
+		},
+		"create with settings": {
+			config: map[string]tftypes.Value{
+				"name":       tftypes.NewValue(tftypes.String, "signals"),
+				"max_tables": tftypes.NewValue(tftypes.Number, 800),
+			},
+			expectedMaxTables:  800,
+			expectedRetention:  604800000000000,
+			expectedPartitions: 1,
+		},
+		"existing database": {
+			config:            map[string]tftypes.Value{"name": tftypes.NewValue(tftypes.String, "signals")},
+			state:             existing,
+			expectedMaxTables: 500,
+			expectedRetention: 0,
+		},
+		"existing database with settings": {
+			config: map[string]tftypes.Value{
+				"name":       tftypes.NewValue(tftypes.String, "signals"),
+				"max_tables": tftypes.NewValue(tftypes.Number, 800),
+			},
+			state:             existing,
+			expectedMaxTables: 800,
+			expectedRetention: 0,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := &DatabaseResource{databaseDefaults: newDatabaseDefaults(&DatabaseDefaultsModel{
+				MaxTables:          types.Int64Value(1000),
+				MaxColumnsPerTable: types.Int64Null(),
+				RetentionPeriod:    types.Int64Value(604800000000000),
+				PartitionTemplate: partitionTemplateValue([]DatabasePartitionTemplateModel{
+					{Type: types.StringValue("tag"), Value: types.StringValue("line")},
+				}),
+			})}
+			resp := testModifyPlan(t, r, testCase.config, testCase.state)
+			testCheckDiagnosticSummaries(t, resp.Diagnostics, "", "")
+
+			var plan DatabaseResourceModel
+			resp.Diagnostics.Append(resp.Plan.Get(t.Context(), &plan)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			if plan.MaxTables.ValueInt64() != testCase.expectedMaxTables {
+				t.Errorf("expected max_tables %d, got %s", testCase.expectedMaxTables, plan.MaxTables)
+			}
+			if plan.MaxColumnsPerTable.ValueInt64() != 200 {
+				t.Errorf("expected max_columns_per_table 200, got %s", plan.MaxColumnsPerTable)
+			}
+			if plan.RetentionPeriod.ValueInt64() != testCase.expectedRetention {
+				t.Errorf("expected retention_period %d, got %s", testCase.expectedRetention, plan.RetentionPeriod)
+			}
+			if got := len(plan.PartitionTemplate.Elements()); got != testCase.expectedPartitions {
+				t.Errorf("expected %d partition template parts, got %d", testCase.expectedPartitions, got)
+			}
+		})
+	}
+}
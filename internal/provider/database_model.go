@@ -2,21 +2,34 @@ package provider
 
 import (
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/thulasirajkomminar/influxdb3-management-go"
 )
 
 // DatabaseModel maps InfluxDB database schema data.
 type DatabaseModel struct {
-	AccountId          types.String                     `tfsdk:"account_id"`
-	ClusterId          types.String                     `tfsdk:"cluster_id"`
-	Name               types.String                     `tfsdk:"name"`
-	MaxTables          types.Int64                      `tfsdk:"max_tables"`
-	MaxColumnsPerTable types.Int64                      `tfsdk:"max_columns_per_table"`
-	RetentionPeriod    types.Int64                      `tfsdk:"retention_period"`
-	PartitionTemplate  []DatabasePartitionTemplateModel `tfsdk:"partition_template"`
+	AccountId          types.String `tfsdk:"account_id"`
+	ClusterId          types.String `tfsdk:"cluster_id"`
+	Name               types.String `tfsdk:"name"`
+	MaxTables          types.Int64  `tfsdk:"max_tables"`
+	MaxColumnsPerTable types.Int64  `tfsdk:"max_columns_per_table"`
+	RetentionPeriod    types.Int64  `tfsdk:"retention_period"`
+	PartitionTemplate  types.List   `tfsdk:"partition_template"`
+}
+
+// DatabaseResourceModel maps InfluxDB database resource schema data.
+type DatabaseResourceModel struct {
+	DatabaseModel
+	AdoptExisting          types.Bool `tfsdk:"adopt_existing"`
+	ReplaceOnLimitDecrease types.Bool `tfsdk:"replace_on_limit_decrease"`
 }
 
 // DatabasePartitionTemplateModel maps InfluxDB database partition template schema data.
@@ -33,6 +46,78 @@ func (d DatabasePartitionTemplateModel) GetAttrType() attr.Type {
 	}}
 }
 
+// DATABASE_NAME_MAX_LENGTH is the maximum length of the cluster name of a database, including the database_name_prefix.
+const DATABASE_NAME_MAX_LENGTH = 64
+
+// databaseNamespace is the database_name_prefix of a provider instance.
+// Database names in the configuration are qualified with it before they are
+// sent to the cluster, and unqualified again when they are read back.
+type databaseNamespace string
+
+// qualify returns the cluster name of a database. All databases ("*") are
+// left as they are.
+func (n databaseNamespace) qualify(name string) string {
+	if name == "*" {
+		return name
+	}
+	return string(n) + name
+}
+
+// validateNameLength checks that the cluster name of the database, including
+// the prefix, is not longer than the cluster allows.
+func (n databaseNamespace) validateNameLength(attributePath path.Path, name types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if name.IsNull() || name.IsUnknown() {
+		return diags
+	}
+
+	if length := len(n.qualify(name.ValueString())); length > DATABASE_NAME_MAX_LENGTH {
+		diags.AddAttributeError(
+			attributePath,
+			"Invalid Attribute Value Length",
+			fmt.Sprintf("The database name must be at most %d characters together with the database_name_prefix %q of the provider, but the cluster name %q is %d characters.", DATABASE_NAME_MAX_LENGTH, n, n.qualify(name.ValueString()), length),
+		)
+	}
+	return diags
+}
+
+// unqualify returns the configuration name of a cluster database, and whether
+// the database is inside the namespace.
+func (n databaseNamespace) unqualify(name string) (string, bool) {
+	if name == "*" {
+		return name, n == ""
+	}
+
+	unqualified, ok := strings.CutPrefix(name, string(n))
+	if !ok || unqualified == "" {
+		return name, false
+	}
+	return unqualified, true
+}
+
+// unqualifyPermissions unqualifies the databases of the permissions, and
+// reports whether all of them are inside the namespace. Databases outside the
+// namespace are left as they are.
+func (n databaseNamespace) unqualifyPermissions(permissions []TokenPermissionModel) ([]TokenPermissionModel, bool) {
+	if n == "" {
+		return permissions, true
+	}
+
+	inside := true
+	unqualifiedPermissions := make([]TokenPermissionModel, 0, len(permissions))
+	for _, permission := range permissions {
+		resource, ok := n.unqualify(permission.Resource.ValueString())
+		inside = inside && ok
+		unqualifiedPermissions = append(unqualifiedPermissions, TokenPermissionModel{
+			Action:   permission.Action,
+			Resource: types.StringValue(resource),
+		})
+	}
+
+	sortPermissions(unqualifiedPermissions)
+	return unqualifiedPermissions, inside
+}
+
 func getDatabaseByName(databases influxdb3.GetClusterDatabasesResponse, name string) (*DatabaseModel, error) {
 	for _, database := range *databases.JSON200 {
 		if database.Name == name {
@@ -56,9 +141,12 @@ func getDatabaseByName(databases influxdb3.GetClusterDatabasesResponse, name str
 	return nil, nil
 }
 
-func getPartitionTemplate(partitionTemplates *influxdb3.ClusterDatabasePartitionTemplate) ([]DatabasePartitionTemplateModel, error) {
-	if partitionTemplates == nil {
-		return nil, nil
+// getPartitionTemplate maps the partition template of a database. Databases
+// without a partition template map to null, whether the API leaves it out or
+// returns no parts.
+func getPartitionTemplate(partitionTemplates *influxdb3.ClusterDatabasePartitionTemplate) (types.List, error) {
+	if partitionTemplates == nil || len(*partitionTemplates) == 0 {
+		return partitionTemplateValue(nil), nil
 	}
 
 	partitionTemplateModels := make([]DatabasePartitionTemplateModel, 0)
@@ -66,12 +154,12 @@ func getPartitionTemplate(partitionTemplates *influxdb3.ClusterDatabasePartition
 		partitionTemplate := make(map[string]any)
 		b, err := v.MarshalJSON()
 		if err != nil {
-			return nil, err
+			return partitionTemplateValue(nil), err
 		}
 
 		err = json.Unmarshal(b, &partitionTemplate)
 		if err != nil {
-			return nil, err
+			return partitionTemplateValue(nil), err
 		}
 
 		if partitionType, ok := partitionTemplate["type"].(string); ok && (partitionType == "time" || partitionType == "tag") {
@@ -84,7 +172,7 @@ func getPartitionTemplate(partitionTemplates *influxdb3.ClusterDatabasePartition
 		} else if partitionTemplate["type"] == "bucket" {
 			jsonEncoded, err := json.Marshal(partitionTemplate["value"])
 			if err != nil {
-				return nil, err
+				return partitionTemplateValue(nil), err
 			}
 
 			partitionTemplateModels = append(partitionTemplateModels, DatabasePartitionTemplateModel{
@@ -93,5 +181,135 @@ func getPartitionTemplate(partitionTemplates *influxdb3.ClusterDatabasePartition
 			})
 		}
 	}
-	return partitionTemplateModels, nil
+	return partitionTemplateValue(partitionTemplateModels), nil
+}
+
+// partitionTemplateValue returns the parts of a partition template as a list
+// value, which is null without parts.
+func partitionTemplateValue(parts []DatabasePartitionTemplateModel) types.List {
+	elementType := DatabasePartitionTemplateModel{}.GetAttrType().(types.ObjectType)
+	if len(parts) == 0 {
+		return types.ListNull(elementType)
+	}
+
+	elements := make([]attr.Value, 0, len(parts))
+	for _, part := range parts {
+		elements = append(elements, types.ObjectValueMust(elementType.AttrTypes, map[string]attr.Value{
+			"type":  part.Type,
+			"value": part.Value,
+		}))
+	}
+	return types.ListValueMust(elementType, elements)
+}
+
+// partitionTemplateParts returns the parts of a partition template, and
+// whether the template is fully known. Unknown parts are left out, so callers
+// that need the complete template must fail when it is not fully known.
+func partitionTemplateParts(partitionTemplate types.List) ([]DatabasePartitionTemplateModel, bool) {
+	parts := []DatabasePartitionTemplateModel{}
+	known := !partitionTemplate.IsUnknown()
+	for _, element := range partitionTemplate.Elements() {
+		object, ok := element.(types.Object)
+		if !ok || object.IsNull() || object.IsUnknown() {
+			known = false
+			continue
+		}
+		partType, _ := object.Attributes()["type"].(types.String)
+		value, _ := object.Attributes()["value"].(types.String)
+		if partType.IsUnknown() || value.IsUnknown() {
+			known = false
+		}
+		parts = append(parts, DatabasePartitionTemplateModel{Type: partType, Value: value})
+	}
+	return parts, known
+}
+
+// partitionTemplateDifferences describes the parts in which the configured and
+// the existing partition templates of a database differ. Templates that are
+// not fully known are not compared.
+func partitionTemplateDifferences(configuredTemplate types.List, existingTemplate types.List) []string {
+	configured, configuredKnown := partitionTemplateParts(configuredTemplate)
+	existing, existingKnown := partitionTemplateParts(existingTemplate)
+	if !configuredKnown || !existingKnown {
+		return nil
+	}
+
+	describe := func(parts []DatabasePartitionTemplateModel, i int) string {
+		if i >= len(parts) {
+			return "(none)"
+		}
+		return fmt.Sprintf("%s %s", parts[i].Type.ValueString(), parts[i].Value.ValueString())
+	}
+
+	var differences []string
+	for i := range max(len(configured), len(existing)) {
+		if i < len(configured) && i < len(existing) && partitionTemplatePartEqual(configured[i], existing[i]) {
+			continue
+		}
+		differences = append(differences, fmt.Sprintf("  partition_template[%d]: configured %s, existing %s", i, describe(configured, i), describe(existing, i)))
+	}
+	return differences
+}
+
+// databaseDifferences describes how the existing database differs from the
+// configured one, one line per attribute. Settings unknown in the
+// configuration are not compared.
+func databaseDifferences(configured DatabaseModel, existing DatabaseModel) []string {
+	differences := partitionTemplateDifferences(configured.PartitionTemplate, existing.PartitionTemplate)
+	for _, setting := range []struct {
+		name       string
+		configured types.Int64
+		existing   types.Int64
+	}{
+		{"max_tables", configured.MaxTables, existing.MaxTables},
+		{"max_columns_per_table", configured.MaxColumnsPerTable, existing.MaxColumnsPerTable},
+		{"retention_period", configured.RetentionPeriod, existing.RetentionPeriod},
+	} {
+		if setting.configured.IsNull() || setting.configured.IsUnknown() || setting.configured.Equal(setting.existing) {
+			continue
+		}
+		differences = append(differences, fmt.Sprintf("  %s: configured %d, existing %d", setting.name, setting.configured.ValueInt64(), setting.existing.ValueInt64()))
+	}
+	return differences
+}
+
+// partitionTemplatePartEqual reports whether two partition template parts are
+// equal, comparing the JSON encoded values of bucket parts semantically.
+func partitionTemplatePartEqual(a DatabasePartitionTemplateModel, b DatabasePartitionTemplateModel) bool {
+	if !a.Type.Equal(b.Type) {
+		return false
+	}
+	if a.Value.Equal(b.Value) {
+		return true
+	}
+	if a.Type.ValueString() != "bucket" {
+		return false
+	}
+
+	var aValue, bValue any
+	if json.Unmarshal([]byte(a.Value.ValueString()), &aValue) != nil || json.Unmarshal([]byte(b.Value.ValueString()), &bValue) != nil {
+		return false
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
+
+// databaseLimitDecreases returns the limits the plan decreases from the state,
+// which the cluster does not allow. Unknown limits are skipped.
+func databaseLimitDecreases(state DatabaseModel, plan DatabaseModel) []path.Path {
+	var decreases []path.Path
+	for name, limits := range map[string][2]types.Int64{
+		"max_columns_per_table": {state.MaxColumnsPerTable, plan.MaxColumnsPerTable},
+		"max_tables":            {state.MaxTables, plan.MaxTables},
+	} {
+		if limits[0].IsNull() || limits[0].IsUnknown() || limits[1].IsNull() || limits[1].IsUnknown() {
+			continue
+		}
+		if limits[1].ValueInt64() < limits[0].ValueInt64() {
+			decreases = append(decreases, path.Root(name))
+		}
+	}
+	slices.SortFunc(decreases, func(a, b path.Path) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return decreases
 }
@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/thulasirajkomminar/influxdb3-management-go"
+)
+
+func TestDatabaseNamespace(t *testing.T) {
+	namespace := databaseNamespace("team_")
+
+	if got := namespace.qualify("signals"); got != "team_signals" {
+		t.Errorf("expected team_signals, got %s", got)
+	}
+	if got := namespace.qualify("*"); got != "*" {
+		t.Errorf("expected *, got %s", got)
+	}
+
+	testCases := map[string]struct {
+		name           string
+		expectedName   string
+		expectedInside bool
+	}{
+		"inside":   {name: "team_signals", expectedName: "signals", expectedInside: true},
+		"outside":  {name: "signals", expectedName: "signals", expectedInside: false},
+		"prefix":   {name: "team_", expectedName: "team_", expectedInside: false},
+		"wildcard": {name: "*", expectedName: "*", expectedInside: false},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, inside := namespace.unqualify(testCase.name)
+			if got != testCase.expectedName || inside != testCase.expectedInside {
+				t.Errorf("expected %s, %t, got %s, %t", testCase.expectedName, testCase.expectedInside, got, inside)
+			}
+		})
+	}
+
+	permissions, inside := namespace.unqualifyPermissions([]TokenPermissionModel{
+		{Action: types.StringValue("write"), Resource: types.StringValue("team_telemetry")},
+		{Action: types.StringValue("read"), Resource: types.StringValue("team_signals")},
+	})
+	if !inside {
+		t.Error("expected permissions to be inside the namespace")
+	}
+	if permissions[0].Resource.ValueString() != "signals" || permissions[1].Resource.ValueString() != "telemetry" {
+		t.Errorf("unexpected permissions: %v", permissions)
+	}
+
+	if _, inside := databaseNamespace("").unqualify("*"); !inside {
+		t.Error("expected all databases to be inside the empty namespace")
+	}
+}
+
+func TestPartitionTemplateDifferences(t *testing.T) {
+	part := func(partType string, value string) DatabasePartitionTemplateModel {
+		return DatabasePartitionTemplateModel{Type: types.StringValue(partType), Value: types.StringValue(value)}
+	}
+
+	testCases := map[string]struct {
+		configured []DatabasePartitionTemplateModel
+		existing   []DatabasePartitionTemplateModel
+		expected   []string
+	}{
+		"none": {},
+		"equal": {
+			configured: []DatabasePartitionTemplateModel{part("tag", "line"), part("bucket", `{"tagName":"temperature","numberOfBuckets":10}`)},
+			existing:   []DatabasePartitionTemplateModel{part("tag", "line"), part("bucket", `{"numberOfBuckets":10,"tagName":"temperature"}`)},
+		},
+		"different value": {
+			configured: []DatabasePartitionTemplateModel{part("time", "%Y-%m-%d")},
+			existing:   []DatabasePartitionTemplateModel{part("time", "%Y-%m")},
+			expected:   []string{"  partition_template[0]: configured time %Y-%m-%d, existing time %Y-%m"},
+		},
+		"missing part": {
+			existing: []DatabasePartitionTemplateModel{part("tag", "line")},
+			expected: []string{"  partition_template[0]: configured (none), existing tag line"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := partitionTemplateDifferences(partitionTemplateValue(testCase.configured), partitionTemplateValue(testCase.existing))
+			if !slices.Equal(got, testCase.expected) {
+				t.Errorf("expected %q, got %q", testCase.expected, got)
+			}
+		})
+	}
+}
+
+func TestDatabaseLimitDecreases(t *testing.T) {
+	database := func(maxTables types.Int64, maxColumnsPerTable types.Int64) DatabaseModel {
+		return DatabaseModel{MaxTables: maxTables, MaxColumnsPerTable: maxColumnsPerTable}
+	}
+
+	testCases := map[string]struct {
+		state    DatabaseModel
+		plan     DatabaseModel
+		expected []string
+	}{
+		"unchanged": {
+			state: database(types.Int64Value(500), types.Int64Value(200)),
+			plan:  database(types.Int64Value(500), types.Int64Value(200)),
+		},
+		"increase": {
+			state: database(types.Int64Value(500), types.Int64Value(200)),
+			plan:  database(types.Int64Value(1000), types.Int64Value(250)),
+		},
+		"decrease": {
+			state:    database(types.Int64Value(500), types.Int64Value(200)),
+			plan:     database(types.Int64Value(100), types.Int64Value(50)),
+			expected: []string{"max_columns_per_table", "max_tables"},
+		},
+		"unknown": {
+			state: database(types.Int64Value(500), types.Int64Value(200)),
+			plan:  database(types.Int64Unknown(), types.Int64Value(200)),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, decrease := range databaseLimitDecreases(testCase.state, testCase.plan) {
+				got = append(got, decrease.String())
+			}
+			if !slices.Equal(got, testCase.expected) {
+				t.Errorf("expected %q, got %q", testCase.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetPartitionTemplateWithoutParts(t *testing.T) {
+	for name, partitionTemplate := range map[string]*influxdb3.ClusterDatabasePartitionTemplate{
+		"missing": nil,
+		"empty":   {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := getPartitionTemplate(partitionTemplate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.IsNull() {
+				t.Errorf("expected a null partition template, got %v", got)
+			}
+		})
+	}
+}
+
+func TestPartitionTemplateParts(t *testing.T) {
+	elementType := DatabasePartitionTemplateModel{}.GetAttrType().(types.ObjectType)
+	partitionTemplate := types.ListValueMust(elementType, []attr.Value{
+		types.ObjectValueMust(elementType.AttrTypes, map[string]attr.Value{
+			"type":  types.StringValue("tag"),
+			"value": types.StringUnknown(),
+		}),
+		types.ObjectUnknown(elementType.AttrTypes),
+		types.ObjectValueMust(elementType.AttrTypes, map[string]attr.Value{
+			"type":  types.StringValue("time"),
+			"value": types.StringValue("%Y-%m-%d"),
+		}),
+	})
+
+	parts, known := partitionTemplateParts(partitionTemplate)
+	if known {
+		t.Error("expected the partition template not to be fully known")
+	}
+	if len(parts) != 2 {
+		t.Fatalf("expected 2 known parts, got %d", len(parts))
+	}
+	if !parts[0].Value.IsUnknown() || parts[1].Value.ValueString() != "%Y-%m-%d" {
+		t.Errorf("unexpected parts: %v", parts)
+	}
+
+	knownTemplate := types.ListValueMust(elementType, partitionTemplate.Elements()[2:])
+	parts, known = partitionTemplateParts(knownTemplate)
+	if !known {
+		t.Error("expected the partition template to be fully known")
+	}
+	if !partitionTemplateValue(parts).Equal(knownTemplate) {
+		t.Errorf("expected the parts to round trip")
+	}
+
+	if differences := partitionTemplateDifferences(partitionTemplate, partitionTemplateValue(nil)); len(differences) > 0 {
+		t.Errorf("expected a partial partition template not to be compared, got %v", differences)
+	}
+}
+
+func TestDatabaseNamespaceValidateNameLength(t *testing.T) {
+	testCases := map[string]struct {
+		namespace     databaseNamespace
+		name          types.String
+		expectedError bool
+	}{
+		"at the limit":               {name: types.StringValue(strings.Repeat("s", 64))},
+		"at the limit with prefix":   {namespace: "team-", name: types.StringValue(strings.Repeat("s", 59))},
+		"over the limit with prefix": {namespace: "team-", name: types.StringValue(strings.Repeat("s", 60)), expectedError: true},
+		"unknown name with prefix":   {namespace: "team-", name: types.StringUnknown()},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			diags := testCase.namespace.validateNameLength(path.Root("name"), testCase.name)
+			if diags.HasError() != testCase.expectedError {
+				t.Errorf("expected error %t, got %v", testCase.expectedError, diags)
+			}
+		})
+	}
+}
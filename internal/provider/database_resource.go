@@ -4,27 +4,37 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/thulasirajkomminar/influxdb3-management-go"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var (
-	_ resource.Resource                = &DatabaseResource{}
-	_ resource.ResourceWithImportState = &DatabaseResource{}
-	_ resource.ResourceWithImportState = &DatabaseResource{}
+	_ resource.Resource                 = &DatabaseResource{}
+	_ resource.ResourceWithImportState  = &DatabaseResource{}
+	_ resource.ResourceWithImportState  = &DatabaseResource{}
+	_ resource.ResourceWithModifyPlan   = &DatabaseResource{}
+	_ resource.ResourceWithMoveState    = &DatabaseResource{}
+	_ resource.ResourceWithUpgradeState = &DatabaseResource{}
 )
 
 // NewDatabaseResource is a helper function to simplify the provider implementation.
@@ -34,9 +44,15 @@ func NewDatabaseResource() resource.Resource {
 
 // DatabaseResource defines the resource implementation.
 type DatabaseResource struct {
-	accountID influxdb3.UuidV4
-	client    influxdb3.ClientWithResponses
-	clusterID influxdb3.UuidV4
+	accountID        influxdb3.UuidV4
+	auditLog         *auditLog
+	client           influxdb3.ClientWithResponses
+	clusterID        influxdb3.UuidV4
+	databaseCache    *databaseCache
+	databaseDefaults *databaseDefaults
+	namespace        databaseNamespace
+	policy           *providerPolicy
+	readOnly         bool
 }
 
 // Metadata returns the resource type name.
@@ -47,51 +63,66 @@ func (r *DatabaseResource) Metadata(ctx context.Context, req resource.MetadataRe
 // Schema defines the schema for the resource.
 func (r *DatabaseResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: DATABASE_RESOURCE_SCHEMA_VERSION,
+
 		// This description is used by the documentation generator and the language server.
-		MarkdownDescription: "Creates and manages a database.",
+		MarkdownDescription: "Creates and manages a database. A create waits until the cluster lists the database, so that database tokens referring to it can be created right after.",
 
 		Attributes: map[string]schema.Attribute{
 			"account_id": schema.StringAttribute{
 				Computed:    true,
 				Description: "The ID of the account that the database belongs to.",
 			},
+			"adopt_existing": schema.BoolAttribute{
+				Computed:    true,
+				Optional:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Whether to take ownership of an existing database with the same name instead of failing to create the database. The partition template of the existing database must match `partition_template`, and `max_tables`, `max_columns_per_table` and `retention_period` are updated to match the configuration. The default is `false`.",
+			},
 			"cluster_id": schema.StringAttribute{
 				Computed:    true,
 				Description: "The ID of the cluster that the database belongs to.",
 			},
 			"name": schema.StringAttribute{
 				Required:    true,
-				Description: "The name of the cluster database. The Length should be between `[ 1 .. 64 ]` characters. **Note:** Database names can't be updated. An update will result in resource replacement. After a database is deleted, you cannot [reuse](https://docs.influxdata.com/influxdb/cloud-dedicated/admin/databases/delete/#cannot-reuse-database-names) the same name for a new database.",
+				Description: "The name of the cluster database. The Length should be between `[ 1 .. 64 ]` characters, including the `database_name_prefix` of the provider. **Note:** Database names can't be updated. An update will result in resource replacement. After a database is deleted, you cannot [reuse](https://docs.influxdata.com/influxdb/cloud-dedicated/admin/databases/delete/#cannot-reuse-database-names) the same name for a new database.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 				Validators: []validator.String{
-					stringvalidator.LengthBetween(1, 64),
+					stringvalidator.LengthBetween(1, DATABASE_NAME_MAX_LENGTH),
 				},
 			},
 			"max_tables": schema.Int64Attribute{
 				Computed:    true,
 				Optional:    true,
-				Default:     int64default.StaticInt64(500),
-				Description: "The maximum number of tables for the cluster database. The default is `500`",
+				Description: "The maximum number of tables for the cluster database. When the database is created, the default is the `max_tables` of the provider `database_defaults`, or `500`. Removing the setting from the configuration of an existing database keeps its current value. **Note:** The cluster does not allow decreasing the limit. A decrease fails the plan, unless `replace_on_limit_decrease` is set.",
+				Validators:  databaseLimitValidators(),
 			},
 			"max_columns_per_table": schema.Int64Attribute{
 				Computed:    true,
 				Optional:    true,
-				Default:     int64default.StaticInt64(200),
-				Description: "The maximum number of columns per table for the cluster database. The default is `200`",
+				Description: "The maximum number of columns per table for the cluster database. When the database is created, the default is the `max_columns_per_table` of the provider `database_defaults`, or `200`. Removing the setting from the configuration of an existing database keeps its current value. **Note:** The cluster does not allow decreasing the limit. A decrease fails the plan, unless `replace_on_limit_decrease` is set.",
+				Validators:  databaseLimitValidators(),
+			},
+			"replace_on_limit_decrease": schema.BoolAttribute{
+				Computed:    true,
+				Optional:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Whether decreasing `max_tables` or `max_columns_per_table` replaces the database, deleting its data, instead of failing the plan. The default is `false`.",
 			},
 			"retention_period": schema.Int64Attribute{
 				Computed:    true,
 				Optional:    true,
-				Default:     int64default.StaticInt64(0),
-				Description: "The retention period of the cluster database in nanoseconds. The default is `0`. If the retention period is not set or is set to `0`, the database will have infinite retention.",
+				Description: "The retention period of the cluster database in nanoseconds. When the database is created, the default is the `retention_period` of the provider `database_defaults`, or `0`. Removing the setting from the configuration of an existing database keeps its current value. If the retention period is not set or is set to `0`, the database will have infinite retention.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
 			},
 			"partition_template": schema.ListNestedAttribute{
 				Computed:    true,
 				Optional:    true,
-				Default:     listdefault.StaticValue(types.ListNull(DatabasePartitionTemplateModel{}.GetAttrType())),
-				Description: "A template for [partitioning](https://docs.influxdata.com/influxdb/cloud-dedicated/admin/custom-partitions/partition-templates/) a cluster database. **Note:** A partition template can include up to 7 total tag and tag bucket parts and only 1 time part. You can only apply a partition template when creating a database. You [can't update a partition template](https://docs.influxdata.com/influxdb/cloud-dedicated/admin/databases/create/#partition-templates-can-only-be-applied-on-create) on an existing database. An update will result in resource replacement.",
+				Description: "A template for [partitioning](https://docs.influxdata.com/influxdb/cloud-dedicated/admin/custom-partitions/partition-templates/) a cluster database. The default is the `partition_template` of the provider `database_defaults` when the database is created. **Note:** A partition template can include up to 7 total tag and tag bucket parts and only 1 time part. You can only apply a partition template when creating a database. You [can't update a partition template](https://docs.influxdata.com/influxdb/cloud-dedicated/admin/databases/create/#partition-templates-can-only-be-applied-on-create) on an existing database. An update will result in resource replacement.",
 				Validators: []validator.List{
 					listvalidator.UniqueValues(),
 					listvalidator.SizeBetween(1, 8),
@@ -120,9 +151,133 @@ func (r *DatabaseResource) Schema(ctx context.Context, req resource.SchemaReques
 	}
 }
 
+// ModifyPlan applies the provider database defaults and checks the plan
+// against the length of prefixed names, the provider policy and the allowed
+// limit changes.
+func (r *DatabaseResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when the resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.applyDefaults(ctx, req, resp)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var name types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(r.namespace.validateNameLength(path.Root("name"), name)...)
+	resp.Diagnostics.Append(r.validatePolicy(ctx, resp.Plan)...)
+	resp.Diagnostics.Append(r.checkLimitDecreases(ctx, req, resp)...)
+}
+
+// applyDefaults sets the settings the configuration leaves unset. They are
+// only set to the provider database defaults when the database is created; an
+// existing database keeps its current settings, so that changing the defaults
+// does not update databases created before.
+func (r *DatabaseResource) applyDefaults(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	defaults := r.databaseDefaults
+	if defaults == nil {
+		defaults = newDatabaseDefaults(nil)
+	}
+
+	for name, value := range map[string]attr.Value{
+		"max_columns_per_table": types.Int64Value(defaults.maxColumnsPerTable),
+		"max_tables":            types.Int64Value(defaults.maxTables),
+		"partition_template":    defaults.partitionTemplate,
+		"retention_period":      types.Int64Value(defaults.retentionPeriod),
+	} {
+		var configValue attr.Value
+		diags.Append(req.Config.GetAttribute(ctx, path.Root(name), &configValue)...)
+		if diags.HasError() {
+			return diags
+		}
+		if !configValue.IsNull() {
+			continue
+		}
+
+		if !req.State.Raw.IsNull() {
+			diags.Append(req.State.GetAttribute(ctx, path.Root(name), &value)...)
+			if diags.HasError() {
+				return diags
+			}
+		}
+		diags.Append(resp.Plan.SetAttribute(ctx, path.Root(name), value)...)
+	}
+
+	return diags
+}
+
+// checkLimitDecreases fails the plan when it decreases limits the cluster does
+// not allow to decrease, or replaces the database when the configuration opts
+// in.
+func (r *DatabaseResource) checkLimitDecreases(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// Nothing to compare when the database is being created
+	if req.State.Raw.IsNull() {
+		return diags
+	}
+
+	var state, plan DatabaseModel
+	var replaceOnLimitDecrease types.Bool
+	diags.Append(req.State.GetAttribute(ctx, path.Root("name"), &state.Name)...)
+	diags.Append(req.State.GetAttribute(ctx, path.Root("max_tables"), &state.MaxTables)...)
+	diags.Append(req.State.GetAttribute(ctx, path.Root("max_columns_per_table"), &state.MaxColumnsPerTable)...)
+	diags.Append(resp.Plan.GetAttribute(ctx, path.Root("max_tables"), &plan.MaxTables)...)
+	diags.Append(resp.Plan.GetAttribute(ctx, path.Root("max_columns_per_table"), &plan.MaxColumnsPerTable)...)
+	diags.Append(resp.Plan.GetAttribute(ctx, path.Root("replace_on_limit_decrease"), &replaceOnLimitDecrease)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	decreases := databaseLimitDecreases(state, plan)
+	if replaceOnLimitDecrease.ValueBool() {
+		resp.RequiresReplace.Append(decreases...)
+		return diags
+	}
+
+	for _, decrease := range decreases {
+		diags.AddAttributeError(
+			decrease,
+			"Database limit decrease not allowed",
+			fmt.Sprintf("The cluster does not allow decreasing %s of database %s. Keep the current limit, or set replace_on_limit_decrease to replace the database, which deletes its data.", decrease.String(), state.Name.ValueString()),
+		)
+	}
+	return diags
+}
+
+// validatePolicy checks the database against the provider policy.
+func (r *DatabaseResource) validatePolicy(ctx context.Context, plan tfsdk.Plan) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if r.policy == nil {
+		return diags
+	}
+
+	var database DatabaseModel
+	diags.Append(plan.GetAttribute(ctx, path.Root("name"), &database.Name)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("max_tables"), &database.MaxTables)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("max_columns_per_table"), &database.MaxColumnsPerTable)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("retention_period"), &database.RetentionPeriod)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	diags.Append(r.policy.validateDatabase(database)...)
+	return diags
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *DatabaseResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan DatabaseModel
+	if r.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic("create", "database"))
+		return
+	}
+
+	var plan DatabaseResourceModel
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -130,9 +285,19 @@ func (r *DatabaseResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
+	parts, known := partitionTemplateParts(plan.PartitionTemplate)
+	if !known {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("partition_template"),
+			"Unknown database partition template",
+			"The partition template of the database is not fully known. Please report this issue to the provider developers.",
+		)
+		return
+	}
+
 	// Generate API request body from plan
 	partitionTemplates := []influxdb3.ClusterDatabasePartitionTemplatePart{}
-	for _, pt := range plan.PartitionTemplate {
+	for _, pt := range parts {
 		t := influxdb3.ClusterDatabasePartitionTemplatePart{}
 		if pt.Type.ValueString() == "time" {
 			timeTemplate := influxdb3.ClusterDatabasePartitionTemplatePartTimeFormat{
@@ -198,12 +363,53 @@ func (r *DatabaseResource) Create(ctx context.Context, req resource.CreateReques
 	createDatabaseRequest := influxdb3.CreateClusterDatabaseJSONRequestBody{
 		MaxTables:          &maxTables,
 		MaxColumnsPerTable: &maxColumnsPerTable,
-		Name:               plan.Name.ValueString(),
+		Name:               r.namespace.qualify(plan.Name.ValueString()),
 		PartitionTemplate:  &partitionTemplates,
 		RetentionPeriod:    plan.RetentionPeriod.ValueInt64Pointer(),
 	}
 
-	createDatabaseResponse, err := r.client.CreateClusterDatabaseWithResponse(ctx, r.accountID, r.clusterID, createDatabaseRequest)
+	createCtx, attempts := withRequestAttempts(ctx)
+	createDatabaseResponse, err := r.client.CreateClusterDatabaseWithResponse(createCtx, r.accountID, r.clusterID, createDatabaseRequest)
+	resp.Diagnostics.Append(r.auditLog.record(auditEntry{
+		Operation: "create_database",
+		AccountID: r.accountID.String(),
+		ClusterID: r.clusterID.String(),
+		Object:    createDatabaseRequest.Name,
+		Request:   createDatabaseRequest,
+		Status:    auditStatus(createDatabaseResponse, err),
+		Error:     auditError(err),
+	})...)
+	r.databaseCache.invalidate()
+
+	// Take ownership of the existing database with the same name
+	if err == nil && createDatabaseResponse.StatusCode() == http.StatusConflict && plan.AdoptExisting.ValueBool() {
+		resp.Diagnostics.Append(r.adoptDatabase(ctx, &plan.DatabaseModel)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
+	// The database may have been created by a request whose response was lost
+	if (err != nil || createDatabaseResponse.StatusCode() != 200) && ambiguousCreate(attempts, auditStatus(createDatabaseResponse, err), err) {
+		createdDatabase, diags := r.findCreatedDatabase(ctx, plan.DatabaseModel)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if createdDatabase != nil {
+			resp.Diagnostics.AddWarning(
+				"Database adopted after ambiguous create",
+				fmt.Sprintf("The request creating database %s failed, but the database exists in the cluster with the planned settings, so it is assumed an earlier attempt of the request created it.", plan.Name.ValueString()),
+			)
+			createdDatabase.Name = plan.Name
+			plan.DatabaseModel = *createdDatabase
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+			return
+		}
+	}
+
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating database",
@@ -234,7 +440,8 @@ func (r *DatabaseResource) Create(ctx context.Context, req resource.CreateReques
 	plan.ClusterId = types.StringValue(createDatabase.ClusterId.String())
 	plan.MaxTables = types.Int64Value(int64(createDatabase.MaxTables))
 	plan.MaxColumnsPerTable = types.Int64Value(int64(createDatabase.MaxColumnsPerTable))
-	plan.Name = types.StringValue(createDatabase.Name)
+	name, _ := r.namespace.unqualify(createDatabase.Name)
+	plan.Name = types.StringValue(name)
 	plan.RetentionPeriod = types.Int64Value(createDatabase.RetentionPeriod)
 
 	partitionTemplate, err := getPartitionTemplate(createDatabase.PartitionTemplate)
@@ -247,6 +454,8 @@ func (r *DatabaseResource) Create(ctx context.Context, req resource.CreateReques
 	}
 	plan.PartitionTemplate = partitionTemplate
 
+	resp.Diagnostics.Append(r.waitForDatabase(ctx, createDatabaseRequest.Name)...)
+
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
@@ -254,50 +463,120 @@ func (r *DatabaseResource) Create(ctx context.Context, req resource.CreateReques
 	}
 }
 
-// Read refreshes the Terraform state with the latest data.
-func (r *DatabaseResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	// Get current state
-	var state DatabaseModel
+// findCreatedDatabase looks up the planned database after an ambiguous
+// create. It returns nil when the database cannot be found, and fails when a
+// database with the name exists but differs from the plan, as it was then not
+// created by the request.
+func (r *DatabaseResource) findCreatedDatabase(ctx context.Context, plan DatabaseModel) (*DatabaseModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	// The create failure is reported when the database cannot be looked up
+	database, lookupDiags := r.getDatabase(ctx, plan.Name.ValueString())
+	if lookupDiags.HasError() || database == nil {
+		return nil, diags
+	}
 
-	// Read Terraform prior state data into the model
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	if resp.Diagnostics.HasError() {
-		return
+	if differences := databaseDifferences(plan, *database); len(differences) > 0 {
+		diags.AddError(
+			"Unable to confirm database create",
+			fmt.Sprintf("The request creating database %s failed, and a database with this name exists in the cluster, but it differs from the configuration, so it was not created by the request:\n\n%s\n\n"+
+				"Set adopt_existing to take ownership of the existing database, or import it.", plan.Name.ValueString(), strings.Join(differences, "\n")),
+		)
+		return nil, diags
 	}
+	return database, diags
+}
 
-	// Get refreshed database value from InfluxDB
-	readDatabasesResponse, err := r.client.GetClusterDatabasesWithResponse(ctx, r.accountID, r.clusterID)
+// getDatabase looks up the database with the configuration name in the
+// cached listing of the cluster databases. It returns nil when the database is
+// not listed.
+func (r *DatabaseResource) getDatabase(ctx context.Context, name string) (*DatabaseModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	readDatabasesResponse, err := r.databaseCache.list(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"Error getting database",
 			err.Error(),
 		)
-		return
+		return nil, diags
 	}
 
 	if readDatabasesResponse.StatusCode() != 200 {
 		errMsg, err := formatErrorResponse(readDatabasesResponse, readDatabasesResponse.StatusCode())
 		if err != nil {
-			resp.Diagnostics.AddError(
+			diags.AddError(
 				"Error formatting error response",
 				err.Error(),
 			)
-			return
+			return nil, diags
 		}
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"Error getting database",
 			errMsg,
 		)
-		return
+		return nil, diags
 	}
 
-	// Check if the database exists
-	readDatabase, err := getDatabaseByName(*readDatabasesResponse, state.Name.ValueString())
+	database, err := getDatabaseByName(*readDatabasesResponse, r.namespace.qualify(name))
 	if err != nil {
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"Error getting database",
 			err.Error(),
 		)
+		return nil, diags
+	}
+	if database != nil {
+		database.Name = types.StringValue(name)
+	}
+	return database, diags
+}
+
+// waitForDatabase lists the databases of the cluster until the database with
+// the qualified name is visible, so that dependent database tokens can be
+// created. It warns when the database is not listed in time.
+func (r *DatabaseResource) waitForDatabase(ctx context.Context, name string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	deadline := time.Now().Add(DATABASE_READY_TIMEOUT)
+	for {
+		// List without the cache, which would keep a listing without the database
+		readDatabasesResponse, err := r.client.GetClusterDatabasesWithResponse(ctx, r.accountID, r.clusterID)
+		if err == nil && readDatabasesResponse.StatusCode() == 200 {
+			database, err := getDatabaseByName(*readDatabasesResponse, name)
+			if err == nil && database != nil {
+				// Listings cached while waiting may lack the database
+				r.databaseCache.invalidate()
+				return diags
+			}
+		}
+
+		if time.Now().Add(DATABASE_READY_INTERVAL).After(deadline) || !sleepContext(ctx, DATABASE_READY_INTERVAL) {
+			diags.AddWarning(
+				"Database not yet available",
+				fmt.Sprintf("The database %s was created, but is not listed by the cluster after %s. Database tokens referring to it may fail to be created until it is.", name, DATABASE_READY_TIMEOUT),
+			)
+			return diags
+		}
+		tflog.Debug(ctx, "Waiting for database to be listed", map[string]any{"name": name})
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *DatabaseResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state DatabaseResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get refreshed database value from InfluxDB
+	readDatabase, diags := r.getDatabase(ctx, state.Name.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 	if readDatabase == nil {
@@ -308,8 +587,18 @@ func (r *DatabaseResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	// Overwrite items with refreshed state
-	state = *readDatabase
+	// Overwrite items with refreshed state, keeping the prior representation
+	// of an equal partition template
+	if len(partitionTemplateDifferences(state.PartitionTemplate, readDatabase.PartitionTemplate)) == 0 {
+		readDatabase.PartitionTemplate = state.PartitionTemplate
+	}
+	state.DatabaseModel = *readDatabase
+	if state.AdoptExisting.IsNull() {
+		state.AdoptExisting = types.BoolValue(false)
+	}
+	if state.ReplaceOnLimitDecrease.IsNull() {
+		state.ReplaceOnLimitDecrease = types.BoolValue(false)
+	}
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
@@ -320,7 +609,12 @@ func (r *DatabaseResource) Read(ctx context.Context, req resource.ReadRequest, r
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *DatabaseResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan DatabaseModel
+	if r.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic("update", "database"))
+		return
+	}
+
+	var plan DatabaseResourceModel
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -328,6 +622,41 @@ func (r *DatabaseResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
+	resp.Diagnostics.Append(r.updateDatabase(ctx, &plan.DatabaseModel)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// updateDatabase updates the database to the planned settings and maps the
+// response into the plan. It fails before updating anything when the listed
+// partition template differs from the plan, as the update cannot change it.
+func (r *DatabaseResource) updateDatabase(ctx context.Context, plan *DatabaseModel) diag.Diagnostics {
+	// The update cannot change the partition template, so check the listed
+	// one still matches the plan
+	readDatabase, diags := r.getDatabase(ctx, plan.Name.ValueString())
+	if diags.HasError() {
+		return diags
+	}
+	if readDatabase != nil {
+		if differences := partitionTemplateDifferences(plan.PartitionTemplate, readDatabase.PartitionTemplate); len(differences) > 0 {
+			diags.AddAttributeError(
+				path.Root("partition_template"),
+				"Database partition template differs",
+				fmt.Sprintf("The partition template of the database %s differs from the configuration. "+
+					"Partition templates cannot be updated, so update the configuration to match the database, or refresh the state to replace it:\n\n%s",
+					plan.Name.ValueString(), strings.Join(differences, "\n")),
+			)
+			return diags
+		}
+	}
+
 	// Generate API request body from plan
 	maxTables := int32(plan.MaxTables.ValueInt64())
 	maxColumnsPerTable := int32(plan.MaxColumnsPerTable.ValueInt64())
@@ -338,29 +667,39 @@ func (r *DatabaseResource) Update(ctx context.Context, req resource.UpdateReques
 	}
 
 	// Update existing database
-	updateDatabaseResponse, err := r.client.UpdateClusterDatabaseWithResponse(ctx, r.accountID, r.clusterID, plan.Name.ValueString(), updateDatabaseRequest)
+	updateDatabaseResponse, err := r.client.UpdateClusterDatabaseWithResponse(ctx, r.accountID, r.clusterID, r.namespace.qualify(plan.Name.ValueString()), updateDatabaseRequest)
+	diags.Append(r.auditLog.record(auditEntry{
+		Operation: "update_database",
+		AccountID: r.accountID.String(),
+		ClusterID: r.clusterID.String(),
+		Object:    r.namespace.qualify(plan.Name.ValueString()),
+		Request:   updateDatabaseRequest,
+		Status:    auditStatus(updateDatabaseResponse, err),
+		Error:     auditError(err),
+	})...)
+	r.databaseCache.invalidate()
 	if err != nil {
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"Error updating database",
 			"Could not update database, unexpected error: "+err.Error(),
 		)
-		return
+		return diags
 	}
 
 	if updateDatabaseResponse.StatusCode() != 200 {
 		errMsg, err := formatErrorResponse(updateDatabaseResponse, updateDatabaseResponse.StatusCode())
 		if err != nil {
-			resp.Diagnostics.AddError(
+			diags.AddError(
 				"Error formatting error response",
 				err.Error(),
 			)
-			return
+			return diags
 		}
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"Error updating database",
 			errMsg,
 		)
-		return
+		return diags
 	}
 	updateDatabase := updateDatabaseResponse.JSON200
 
@@ -369,19 +708,58 @@ func (r *DatabaseResource) Update(ctx context.Context, req resource.UpdateReques
 	plan.ClusterId = types.StringValue(updateDatabase.ClusterId.String())
 	plan.MaxTables = types.Int64Value(int64(updateDatabase.MaxTables))
 	plan.MaxColumnsPerTable = types.Int64Value(int64(updateDatabase.MaxColumnsPerTable))
-	plan.Name = types.StringValue(updateDatabase.Name)
+	name, _ := r.namespace.unqualify(updateDatabase.Name)
+	plan.Name = types.StringValue(name)
 	plan.RetentionPeriod = types.Int64Value(updateDatabase.RetentionPeriod)
 
-	// Save updated data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
+	return diags
+}
+
+// adoptDatabase takes ownership of the existing database with the planned
+// name. The partition template cannot be updated, so it must match the plan;
+// the other settings are updated to match the plan.
+func (r *DatabaseResource) adoptDatabase(ctx context.Context, plan *DatabaseModel) diag.Diagnostics {
+	existingDatabase, diags := r.getDatabase(ctx, plan.Name.ValueString())
+	if diags.HasError() {
+		return diags
 	}
+	if existingDatabase == nil {
+		diags.AddError(
+			"Database not found",
+			fmt.Sprintf("Database with name %s already exists according to the cluster, but is not listed", plan.Name.ValueString()),
+		)
+		return diags
+	}
+
+	if differences := partitionTemplateDifferences(plan.PartitionTemplate, existingDatabase.PartitionTemplate); len(differences) > 0 {
+		diags.AddAttributeError(
+			path.Root("partition_template"),
+			"Unable to adopt existing database",
+			fmt.Sprintf("The database %s already exists with a partition template that differs from the configuration. "+
+				"Partition templates cannot be updated, so update the configuration to match the existing database:\n\n%s",
+				plan.Name.ValueString(), strings.Join(differences, "\n")),
+		)
+		return diags
+	}
+
+	tflog.Info(ctx, "Adopting existing database", map[string]any{"name": plan.Name.ValueString()})
+
+	if existingDatabase.MaxTables.Equal(plan.MaxTables) && existingDatabase.MaxColumnsPerTable.Equal(plan.MaxColumnsPerTable) && existingDatabase.RetentionPeriod.Equal(plan.RetentionPeriod) {
+		plan.AccountId = existingDatabase.AccountId
+		plan.ClusterId = existingDatabase.ClusterId
+		return diags
+	}
+	return r.updateDatabase(ctx, plan)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *DatabaseResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state DatabaseModel
+	if r.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic("delete", "database"))
+		return
+	}
+
+	var state DatabaseResourceModel
 
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
@@ -390,7 +768,16 @@ func (r *DatabaseResource) Delete(ctx context.Context, req resource.DeleteReques
 	}
 
 	// Delete existing database
-	deleteDatabasesResponse, err := r.client.DeleteClusterDatabaseWithResponse(ctx, r.accountID, r.clusterID, state.Name.ValueString())
+	deleteDatabasesResponse, err := r.client.DeleteClusterDatabaseWithResponse(ctx, r.accountID, r.clusterID, r.namespace.qualify(state.Name.ValueString()))
+	resp.Diagnostics.Append(r.auditLog.record(auditEntry{
+		Operation: "delete_database",
+		AccountID: r.accountID.String(),
+		ClusterID: r.clusterID.String(),
+		Object:    r.namespace.qualify(state.Name.ValueString()),
+		Status:    auditStatus(deleteDatabasesResponse, err),
+		Error:     auditError(err),
+	})...)
+	r.databaseCache.invalidate()
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting database",
@@ -433,10 +820,66 @@ func (r *DatabaseResource) Configure(ctx context.Context, req resource.Configure
 	}
 
 	r.accountID = pd.accountID
+	r.auditLog = pd.auditLog
 	r.client = pd.client
 	r.clusterID = pd.clusterID
+	r.databaseCache = pd.databaseCache
+	r.databaseDefaults = pd.databaseDefaults
+	r.namespace = pd.namespace
+	r.policy = pd.policy
+	r.readOnly = pd.readOnly
 }
 
+// ImportState imports a database by name, populating the state, including the
+// partition template, from the cluster.
 func (r *DatabaseResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Retrieve import name and save to name attribute
 	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
 }
+
+// MoveState moves the state of a database managed under the provider's former
+// komminarlabs/influxdb3 namespace into this resource.
+func (r *DatabaseResource) MoveState(ctx context.Context) []resource.StateMover {
+	sourceSchema := databaseResourceSchemaV0()
+
+	return []resource.StateMover{
+		{
+			SourceSchema: &sourceSchema,
+			StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+				if !isLegacyProviderAddress(req.SourceProviderAddress) || req.SourceTypeName != "influxdb3_database" {
+					return
+				}
+
+				if req.SourceSchemaVersion != 0 || req.SourceState == nil {
+					resp.Diagnostics.AddError(
+						"Unable to move database state",
+						fmt.Sprintf("The %s resource state with schema version %d cannot be moved. Please report this issue to the provider developers.", req.SourceTypeName, req.SourceSchemaVersion),
+					)
+					return
+				}
+
+				var sourceState databaseModelV0
+
+				resp.Diagnostics.Append(req.SourceState.Get(ctx, &sourceState)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				state := sourceState.upgrade()
+				resp.Diagnostics.Append(resp.TargetState.Set(ctx, &state)...)
+			},
+		},
+	}
+}
+
+// UpgradeState upgrades the state of a database written by a prior schema version.
+func (r *DatabaseResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	schemaV0 := databaseResourceSchemaV0()
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   &schemaV0,
+			StateUpgrader: upgradeDatabaseStateV0,
+		},
+	}
+}
@@ -0,0 +1,240 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestDatabaseResourceCreateAmbiguous(t *testing.T) {
+	testCases := map[string]struct {
+		existing        map[string]any
+		storeDatabase   bool
+		expectedError   string
+		expectedWarning string
+	}{
+		"database created before the failure": {
+			storeDatabase:   true,
+			expectedWarning: "Database adopted after ambiguous create",
+		},
+		"database not created": {
+			expectedError: "Error creating database",
+		},
+		"other database with the name": {
+			existing:      map[string]any{"name": "signals", "maxTables": 100, "maxColumnsPerTable": 200, "retentionPeriod": 0},
+			expectedError: "Unable to confirm database create",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			if testCase.existing != nil {
+				api.addDatabase(testCase.existing)
+			}
+			api.intercept = func(w http.ResponseWriter, r *http.Request, resource string) bool {
+				if r.Method != http.MethodPost {
+					return true
+				}
+				if testCase.storeDatabase {
+					api.serve(httptest.NewRecorder(), r, resource)
+				}
+				writeFakeError(w, http.StatusInternalServerError, "internal error")
+				return false
+			}
+
+			r := &DatabaseResource{
+				client:        api.client,
+				accountID:     api.accountID,
+				clusterID:     api.clusterID,
+				databaseCache: newDatabaseCache(api.client, api.accountID, api.clusterID),
+			}
+			plan := testResourceObject(t, r, map[string]tftypes.Value{
+				"name":                  tftypes.NewValue(tftypes.String, "signals"),
+				"max_tables":            tftypes.NewValue(tftypes.Number, 500),
+				"max_columns_per_table": tftypes.NewValue(tftypes.Number, 200),
+				"retention_period":      tftypes.NewValue(tftypes.Number, 0),
+			})
+			resp := &resource.CreateResponse{State: testResourceObject(t, r, nil)}
+			r.Create(t.Context(), resource.CreateRequest{Plan: tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw}}, resp)
+
+			testCheckDiagnosticSummaries(t, resp.Diagnostics, testCase.expectedError, testCase.expectedWarning)
+			if testCase.expectedError != "" {
+				return
+			}
+
+			var state DatabaseResourceModel
+			resp.Diagnostics.Append(resp.State.Get(t.Context(), &state)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			if state.Name.ValueString() != "signals" || state.MaxTables.ValueInt64() != 500 {
+				t.Errorf("expected the state of the planned database, got %s with max_tables %s", state.Name, state.MaxTables)
+			}
+		})
+	}
+}
+
+func TestDatabaseResourceCreateAdoptExisting(t *testing.T) {
+	testCases := map[string]struct {
+		adoptExisting     bool
+		existing          map[string]any
+		expectedError     string
+		expectedMaxTables int64
+		expectedUpdate    bool
+	}{
+		"adopted": {
+			adoptExisting:     true,
+			existing:          map[string]any{"name": "signals", "maxTables": 100, "maxColumnsPerTable": 200, "retentionPeriod": 0},
+			expectedMaxTables: 500,
+			expectedUpdate:    true,
+		},
+		"adopted unchanged": {
+			adoptExisting:     true,
+			existing:          map[string]any{"name": "signals", "maxTables": 500, "maxColumnsPerTable": 200, "retentionPeriod": 0},
+			expectedMaxTables: 500,
+		},
+		"partition template differs": {
+			adoptExisting: true,
+			existing: map[string]any{"name": "signals", "maxTables": 100, "maxColumnsPerTable": 200, "retentionPeriod": 0,
+				"partitionTemplate": []map[string]any{{"type": "tag", "value": "line"}}},
+			expectedError: "Unable to adopt existing database",
+		},
+		"adopt_existing not set": {
+			existing:      map[string]any{"name": "signals", "maxTables": 100, "maxColumnsPerTable": 200, "retentionPeriod": 0},
+			expectedError: "Error creating database",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.addDatabase(testCase.existing)
+
+			r := &DatabaseResource{
+				client:        api.client,
+				accountID:     api.accountID,
+				clusterID:     api.clusterID,
+				databaseCache: newDatabaseCache(api.client, api.accountID, api.clusterID),
+			}
+			plan := testResourceObject(t, r, map[string]tftypes.Value{
+				"name":                  tftypes.NewValue(tftypes.String, "signals"),
+				"adopt_existing":        tftypes.NewValue(tftypes.Bool, testCase.adoptExisting),
+				"max_tables":            tftypes.NewValue(tftypes.Number, 500),
+				"max_columns_per_table": tftypes.NewValue(tftypes.Number, 200),
+				"retention_period":      tftypes.NewValue(tftypes.Number, 0),
+			})
+			resp := &resource.CreateResponse{State: testResourceObject(t, r, nil)}
+			r.Create(t.Context(), resource.CreateRequest{Plan: tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw}}, resp)
+
+			testCheckDiagnosticSummaries(t, resp.Diagnostics, testCase.expectedError, "")
+			if got := slices.Contains(api.requestLog(), "PATCH /databases/signals"); got != testCase.expectedUpdate {
+				t.Errorf("expected the existing database to be updated: %t, got %t", testCase.expectedUpdate, got)
+			}
+			if testCase.expectedError != "" {
+				return
+			}
+
+			var state DatabaseResourceModel
+			resp.Diagnostics.Append(resp.State.Get(t.Context(), &state)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			if state.MaxTables.ValueInt64() != testCase.expectedMaxTables || state.AccountId.ValueString() != api.accountID.String() {
+				t.Errorf("expected the state of the adopted database with max_tables %d, got max_tables %s and account_id %s", testCase.expectedMaxTables, state.MaxTables, state.AccountId)
+			}
+		})
+	}
+}
+
+func TestDatabaseResourceUpdatePartitionTemplate(t *testing.T) {
+	testCases := map[string]struct {
+		partitionTemplate []map[string]any
+		expectedError     string
+		expectedUpdate    bool
+	}{
+		"matching": {
+			partitionTemplate: []map[string]any{{"type": "tag", "value": "line"}},
+			expectedUpdate:    true,
+		},
+		"differs": {
+			partitionTemplate: []map[string]any{{"type": "tag", "value": "sensor"}},
+			expectedError:     "Database partition template differs",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.addDatabase(map[string]any{"name": "signals", "maxTables": 500, "maxColumnsPerTable": 200, "retentionPeriod": 0,
+				"partitionTemplate": testCase.partitionTemplate})
+
+			r := &DatabaseResource{
+				client:        api.client,
+				accountID:     api.accountID,
+				clusterID:     api.clusterID,
+				databaseCache: newDatabaseCache(api.client, api.accountID, api.clusterID),
+			}
+			plan := DatabaseModel{
+				Name:               types.StringValue("signals"),
+				MaxTables:          types.Int64Value(600),
+				MaxColumnsPerTable: types.Int64Value(200),
+				RetentionPeriod:    types.Int64Value(0),
+				PartitionTemplate: partitionTemplateValue([]DatabasePartitionTemplateModel{
+					{Type: types.StringValue("tag"), Value: types.StringValue("line")},
+				}),
+			}
+			diags := r.updateDatabase(t.Context(), &plan)
+
+			testCheckDiagnosticSummaries(t, diags, testCase.expectedError, "")
+			if got := slices.Contains(api.requestLog(), "PATCH /databases/signals"); got != testCase.expectedUpdate {
+				t.Errorf("expected the database to be updated: %t, got %t", testCase.expectedUpdate, got)
+			}
+			if testCase.expectedError == "" && plan.MaxTables.ValueInt64() != 600 {
+				t.Errorf("expected max_tables 600, got %s", plan.MaxTables)
+			}
+		})
+	}
+}
+
+func TestDatabaseResourceReadImported(t *testing.T) {
+	api := newFakeAPI(t)
+	api.addDatabase(map[string]any{"name": "prod_signals", "maxTables": 500, "maxColumnsPerTable": 200, "retentionPeriod": 0,
+		"partitionTemplate": []map[string]any{{"type": "tag", "value": "line"}, {"type": "time", "value": "%Y-%m-%d"}}})
+
+	r := &DatabaseResource{
+		client:        api.client,
+		accountID:     api.accountID,
+		clusterID:     api.clusterID,
+		databaseCache: newDatabaseCache(api.client, api.accountID, api.clusterID),
+		namespace:     databaseNamespace("prod_"),
+	}
+	importResp := &resource.ImportStateResponse{State: testResourceObject(t, r, nil)}
+	r.ImportState(t.Context(), resource.ImportStateRequest{ID: "signals"}, importResp)
+	testCheckDiagnosticSummaries(t, importResp.Diagnostics, "", "")
+
+	resp := &resource.ReadResponse{State: importResp.State}
+	r.Read(t.Context(), resource.ReadRequest{State: importResp.State}, resp)
+	testCheckDiagnosticSummaries(t, resp.Diagnostics, "", "")
+
+	var state DatabaseResourceModel
+	resp.Diagnostics.Append(resp.State.Get(t.Context(), &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+	if state.Name.ValueString() != "signals" || state.AccountId.ValueString() != api.accountID.String() {
+		t.Errorf("expected the imported database signals, got %s in account %s", state.Name, state.AccountId)
+	}
+	if got := len(state.PartitionTemplate.Elements()); got != 2 {
+		t.Errorf("expected 2 partition template parts, got %d", got)
+	}
+	if state.AdoptExisting.IsNull() || state.ReplaceOnLimitDecrease.IsNull() {
+		t.Errorf("expected adopt_existing and replace_on_limit_decrease to be set, got %s and %s", state.AdoptExisting, state.ReplaceOnLimitDecrease)
+	}
+}
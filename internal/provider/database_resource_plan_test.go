@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestDatabaseResourceModifyPlanUnknownPartitionTemplatePart(t *testing.T) {
+	partType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"type": tftypes.String, "value": tftypes.String}}
+	partitionTemplate := tftypes.NewValue(tftypes.List{ElementType: partType}, []tftypes.Value{
+		tftypes.NewValue(partType, map[string]tftypes.Value{
+			"type":  tftypes.NewValue(tftypes.String, "tag"),
+			"value": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		}),
+		tftypes.NewValue(partType, tftypes.UnknownValue),
+	})
+
+	testCases := map[string]struct {
+		state map[string]tftypes.Value
+	}{
+		"create": {},
+		"existing database": {
+			state: map[string]tftypes.Value{
+				"name":                  tftypes.NewValue(tftypes.String, "signals"),
+				"max_tables":            tftypes.NewValue(tftypes.Number, 500),
+				"max_columns_per_table": tftypes.NewValue(tftypes.Number, 200),
+				"retention_period":      tftypes.NewValue(tftypes.Number, 0),
+				"partition_template": tftypes.NewValue(tftypes.List{ElementType: partType}, []tftypes.Value{
+					tftypes.NewValue(partType, map[string]tftypes.Value{
+						"type":  tftypes.NewValue(tftypes.String, "tag"),
+						"value": tftypes.NewValue(tftypes.String, "line"),
+					}),
+				}),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := &DatabaseResource{databaseDefaults: newDatabaseDefaults(&DatabaseDefaultsModel{
+				MaxTables:          types.Int64Null(),
+				MaxColumnsPerTable: types.Int64Null(),
+				RetentionPeriod:    types.Int64Null(),
+				PartitionTemplate: partitionTemplateValue([]DatabasePartitionTemplateModel{
+					{Type: types.StringValue("time"), Value: types.StringValue("%Y-%m-%d")},
+				}),
+			})}
+			resp := testModifyPlan(t, r, map[string]tftypes.Value{
+				"name":               tftypes.NewValue(tftypes.String, "signals"),
+				"partition_template": partitionTemplate,
+			}, testCase.state)
+			testCheckDiagnosticSummaries(t, resp.Diagnostics, "", "")
+
+			var plan DatabaseResourceModel
+			resp.Diagnostics.Append(resp.Plan.Get(t.Context(), &plan)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			parts, known := partitionTemplateParts(plan.PartitionTemplate)
+			if known || len(plan.PartitionTemplate.Elements()) != 2 {
+				t.Errorf("expected the configured partition template with unknown parts, got %s", plan.PartitionTemplate)
+			}
+			if len(parts) != 1 || parts[0].Type.ValueString() != "tag" {
+				t.Errorf("expected the known tag part, got %v", parts)
+			}
+		})
+	}
+}
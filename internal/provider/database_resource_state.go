@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// DATABASE_RESOURCE_SCHEMA_VERSION is the current schema version of the influxdb3_database resource.
+const DATABASE_RESOURCE_SCHEMA_VERSION = 1
+
+// databaseModelV0 maps version 0 state of the influxdb3_database resource.
+type databaseModelV0 struct {
+	AccountId          types.String                     `tfsdk:"account_id"`
+	ClusterId          types.String                     `tfsdk:"cluster_id"`
+	Name               types.String                     `tfsdk:"name"`
+	MaxTables          types.Int64                      `tfsdk:"max_tables"`
+	MaxColumnsPerTable types.Int64                      `tfsdk:"max_columns_per_table"`
+	RetentionPeriod    types.Int64                      `tfsdk:"retention_period"`
+	PartitionTemplate  []DatabasePartitionTemplateModel `tfsdk:"partition_template"`
+}
+
+// upgrade converts version 0 state into the current DatabaseResourceModel.
+func (m databaseModelV0) upgrade() DatabaseResourceModel {
+	return DatabaseResourceModel{
+		DatabaseModel: DatabaseModel{
+			AccountId:          m.AccountId,
+			ClusterId:          m.ClusterId,
+			Name:               m.Name,
+			MaxTables:          m.MaxTables,
+			MaxColumnsPerTable: m.MaxColumnsPerTable,
+			RetentionPeriod:    m.RetentionPeriod,
+			PartitionTemplate:  partitionTemplateValue(m.PartitionTemplate),
+		},
+		AdoptExisting:          types.BoolValue(false),
+		ReplaceOnLimitDecrease: types.BoolValue(false),
+	}
+}
+
+// upgradeDatabaseStateV0 upgrades version 0 state to the current schema version.
+func upgradeDatabaseStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var priorState databaseModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &priorState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state := priorState.upgrade()
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// databaseResourceSchemaV0 is the version 0 schema of the influxdb3_database
+// resource. It is the schema published under the komminarlabs/influxdb3
+// namespace and is kept to decode state written by those releases.
+func databaseResourceSchemaV0() schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"account_id": schema.StringAttribute{
+				Computed: true,
+			},
+			"cluster_id": schema.StringAttribute{
+				Computed: true,
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+			"max_tables": schema.Int64Attribute{
+				Computed: true,
+				Optional: true,
+			},
+			"max_columns_per_table": schema.Int64Attribute{
+				Computed: true,
+				Optional: true,
+			},
+			"retention_period": schema.Int64Attribute{
+				Computed: true,
+				Optional: true,
+			},
+			"partition_template": schema.ListNestedAttribute{
+				Computed: true,
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							Required: true,
+						},
+						"value": schema.StringAttribute{
+							Required: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
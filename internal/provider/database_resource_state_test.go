@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+)
+
+func TestDatabaseResourceUpgradeStateV0(t *testing.T) {
+	state := testUpgradeResourceState(t, NewDatabaseResource(), "influxdb3_database", 0, "database_state_v0.json")
+	testCheckDatabaseStateV0(t, state)
+}
+
+func TestDatabaseResourceMoveStateFromLegacyProvider(t *testing.T) {
+	state := testMoveResourceState(t, NewDatabaseResource(), "registry.terraform.io/komminarlabs/influxdb3", "influxdb3_database", "database_state_v0.json")
+	testCheckDatabaseStateV0(t, state)
+}
+
+func testCheckDatabaseStateV0(t *testing.T, state tfsdk.State) {
+	t.Helper()
+
+	var database DatabaseResourceModel
+	if diags := state.Get(t.Context(), &database); diags.HasError() {
+		t.Fatalf("unable to read upgraded state: %v", diags)
+	}
+
+	if database.AdoptExisting.ValueBool() {
+		t.Errorf("expected adopt_existing false, got true")
+	}
+	if got := database.Name.ValueString(); got != "signals" {
+		t.Errorf("expected name signals, got %s", got)
+	}
+	if got := database.AccountId.ValueString(); got != "0b8a4bd6-6d1f-4d4d-9a5c-2f6f2c3a9e11" {
+		t.Errorf("expected account_id 0b8a4bd6-6d1f-4d4d-9a5c-2f6f2c3a9e11, got %s", got)
+	}
+	if got := database.MaxTables.ValueInt64(); got != 500 {
+		t.Errorf("expected max_tables 500, got %d", got)
+	}
+	if got := database.MaxColumnsPerTable.ValueInt64(); got != 200 {
+		t.Errorf("expected max_columns_per_table 200, got %d", got)
+	}
+	if got := database.RetentionPeriod.ValueInt64(); got != 604800000000000 {
+		t.Errorf("expected retention_period 604800000000000, got %d", got)
+	}
+
+	expectedPartitionTemplate := [][2]string{
+		{"tag", "line"},
+		{"time", "%Y-%m-%d"},
+		{"bucket", `{"numberOfBuckets":10,"tagName":"temperature"}`},
+	}
+	partitionTemplate, known := partitionTemplateParts(database.PartitionTemplate)
+	if !known || len(partitionTemplate) != len(expectedPartitionTemplate) {
+		t.Fatalf("expected %d partition template parts, got %d", len(expectedPartitionTemplate), len(partitionTemplate))
+	}
+	for i, expected := range expectedPartitionTemplate {
+		part := partitionTemplate[i]
+		if part.Type.ValueString() != expected[0] || part.Value.ValueString() != expected[1] {
+			t.Errorf("expected partition template part %d to be %s=%s, got %s=%s", i, expected[0], expected[1], part.Type.ValueString(), part.Value.ValueString())
+		}
+	}
+}
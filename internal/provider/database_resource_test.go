@@ -1,11 +1,16 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"regexp"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/thulasirajkomminar/influxdb3-management-go"
 )
 
 func TestAccDatabaseResource(t *testing.T) {
@@ -41,6 +46,114 @@ func TestAccDatabaseResource(t *testing.T) {
 	})
 }
 
+func TestAccDatabaseResourceImportPartitionTemplate(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccDatabaseResourcePartitionTemplateConfig("test_partitioned", 500),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("influxdb3_database.test", "partition_template.#", "3"),
+					resource.TestCheckResourceAttr("influxdb3_database.test", "partition_template.0.type", "tag"),
+					resource.TestCheckResourceAttr("influxdb3_database.test", "partition_template.2.value", `{"numberOfBuckets":10,"tagName":"sensor"}`),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:                         "influxdb3_database.test",
+				ImportState:                          true,
+				ImportStateId:                        "test_partitioned",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+			},
+			// The imported partition template plans no changes
+			{
+				Config:             providerConfig + testAccDatabaseResourcePartitionTemplateConfig("test_partitioned", 500),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccDatabaseResourcePartitionTemplateConfig("test_partitioned", 600),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("influxdb3_database.test", "max_tables", "600"),
+					resource.TestCheckResourceAttr("influxdb3_database.test", "partition_template.#", "3"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccDatabaseResourceAdoptExisting(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// A database created outside Terraform is not adopted by default
+			{
+				PreConfig:   func() { testAccCreateDatabase(t, "test_adopted", 100) },
+				Config:      providerConfig + testAccDatabaseResourceAdoptExistingConfig("test_adopted", false),
+				ExpectError: regexp.MustCompile("Error creating database"),
+			},
+			// Create adopting the existing database and Read testing
+			{
+				Config: providerConfig + testAccDatabaseResourceAdoptExistingConfig("test_adopted", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("influxdb3_database.test", "name", "test_adopted"),
+					resource.TestCheckResourceAttr("influxdb3_database.test", "max_tables", "500"),
+					resource.TestCheckResourceAttrSet("influxdb3_database.test", "account_id"),
+				),
+			},
+			// The adopted database plans no changes
+			{
+				Config:             providerConfig + testAccDatabaseResourceAdoptExistingConfig("test_adopted", true),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+// testAccCreateDatabase creates a database outside Terraform with the
+// credentials of the acceptance tests.
+func testAccCreateDatabase(t *testing.T, name string, maxTables int32) {
+	t.Helper()
+
+	token := os.Getenv("INFLUXDB3_TOKEN")
+	client, err := influxdb3.NewClientWithResponses(INFLUXDB3_HOST+INFLUXDB3_API_ENDPOINT, influxdb3.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("unable to create client: %s", err)
+	}
+
+	accountID, err := uuid.Parse(os.Getenv("INFLUXDB3_ACCOUNT_ID"))
+	if err != nil {
+		t.Fatalf("unable to parse INFLUXDB3_ACCOUNT_ID: %s", err)
+	}
+	clusterID, err := uuid.Parse(os.Getenv("INFLUXDB3_CLUSTER_ID"))
+	if err != nil {
+		t.Fatalf("unable to parse INFLUXDB3_CLUSTER_ID: %s", err)
+	}
+
+	createDatabaseResponse, err := client.CreateClusterDatabaseWithResponse(t.Context(), accountID, clusterID, influxdb3.CreateClusterDatabaseJSONRequestBody{
+		Name:      name,
+		MaxTables: &maxTables,
+	})
+	if err != nil {
+		t.Fatalf("unable to create database %s: %s", name, err)
+	}
+	if createDatabaseResponse.StatusCode() != 200 {
+		t.Fatalf("unable to create database %s: %s", name, createDatabaseResponse.Body)
+	}
+}
+
 func testAccDatabaseResourceWithRetentionConfig(name string, description string, retention_period string) string {
 	return fmt.Sprintf(`
 resource "influxdb3_database" "test" {
@@ -61,3 +174,40 @@ resource "influxdb3_database" "test" {
 }
 `, name, description)
 }
+
+func testAccDatabaseResourceAdoptExistingConfig(name string, adoptExisting bool) string {
+	return fmt.Sprintf(`
+resource "influxdb3_database" "test" {
+  name           = %[1]q
+  max_tables     = 500
+  adopt_existing = %[2]t
+}
+`, name, adoptExisting)
+}
+
+func testAccDatabaseResourcePartitionTemplateConfig(name string, maxTables int) string {
+	return fmt.Sprintf(`
+resource "influxdb3_database" "test" {
+  name       = %[1]q
+  max_tables = %[2]d
+
+  partition_template = [
+    {
+      type  = "tag"
+      value = "line"
+    },
+    {
+      type  = "time"
+      value = "%%Y-%%m-%%d"
+    },
+    {
+      type = "bucket"
+      value = jsonencode({
+        "tagName" : "sensor",
+        "numberOfBuckets" : 10
+      })
+    },
+  ]
+}
+`, name, maxTables)
+}
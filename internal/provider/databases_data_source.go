@@ -3,9 +3,11 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/thulasirajkomminar/influxdb3-management-go"
 )
@@ -23,14 +25,19 @@ func NewDatabasesDataSource() datasource.DataSource {
 
 // DatabasesDataSource is the data source implementation.
 type DatabasesDataSource struct {
-	accountID influxdb3.UuidV4
-	client    influxdb3.ClientWithResponses
-	clusterID influxdb3.UuidV4
+	accountID     influxdb3.UuidV4
+	client        influxdb3.ClientWithResponses
+	clusterID     influxdb3.UuidV4
+	databaseCache *databaseCache
+	namespace     databaseNamespace
 }
 
 // DatabasesDataSourceModel describes the data source data model.
 type DatabasesDataSourceModel struct {
-	Databases []DatabaseModel `tfsdk:"databases"`
+	Databases       []DatabaseModel          `tfsdk:"databases"`
+	DatabasesByName map[string]DatabaseModel `tfsdk:"databases_by_name"`
+	NameRegex       types.String             `tfsdk:"name_regex"`
+	Names           []types.String           `tfsdk:"names"`
 }
 
 // Metadata returns the data source type name.
@@ -42,53 +49,78 @@ func (d *DatabasesDataSource) Metadata(ctx context.Context, req datasource.Metad
 func (d *DatabasesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
-		Description: "Gets all databases for a cluster.",
+		Description: "Gets all databases for a cluster, optionally filtered.",
 
 		Attributes: map[string]schema.Attribute{
 			"databases": schema.ListNestedAttribute{
 				Computed: true,
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"account_id": schema.StringAttribute{
-							Computed:    true,
-							Description: "The ID of the account that the database belongs to.",
-						},
-						"cluster_id": schema.StringAttribute{
-							Computed:    true,
-							Description: "The ID of the cluster that the database belongs to.",
-						},
-						"name": schema.StringAttribute{
-							Computed:    true,
-							Description: "The name of the cluster database.",
-						},
-						"max_tables": schema.Int64Attribute{
-							Computed:    true,
-							Description: "The maximum number of tables for the cluster database.",
-						},
-						"max_columns_per_table": schema.Int64Attribute{
-							Computed:    true,
-							Description: "The maximum number of columns per table for the cluster database.",
-						},
-						"retention_period": schema.Int64Attribute{
-							Computed:    true,
-							Description: "The retention period of the cluster database in nanoseconds.",
-						},
-						"partition_template": schema.ListNestedAttribute{
-							Computed:    true,
-							Description: "The template partitioning of the cluster database.",
-							NestedObject: schema.NestedAttributeObject{
-								Attributes: map[string]schema.Attribute{
-									"type": schema.StringAttribute{
-										Computed:    true,
-										Description: "The type of template part.",
-									},
-									"value": schema.StringAttribute{
-										Computed:    true,
-										Description: "The value of template part.",
-									},
-								},
-							},
-						},
+					Attributes: databaseDataSourceAttributes(),
+				},
+			},
+			"databases_by_name": schema.MapNestedAttribute{
+				Computed:    true,
+				Description: "The cluster databases keyed by name.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: databaseDataSourceAttributes(),
+				},
+			},
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return cluster databases whose name matches this regular expression.",
+				Validators: []validator.String{
+					regexpValidator{},
+				},
+			},
+			"names": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The names of the cluster databases.",
+			},
+		},
+	}
+}
+
+// databaseDataSourceAttributes returns the attributes of a cluster database
+// returned by the data source.
+func databaseDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"account_id": schema.StringAttribute{
+			Computed:    true,
+			Description: "The ID of the account that the database belongs to.",
+		},
+		"cluster_id": schema.StringAttribute{
+			Computed:    true,
+			Description: "The ID of the cluster that the database belongs to.",
+		},
+		"name": schema.StringAttribute{
+			Computed:    true,
+			Description: "The name of the cluster database.",
+		},
+		"max_tables": schema.Int64Attribute{
+			Computed:    true,
+			Description: "The maximum number of tables for the cluster database.",
+		},
+		"max_columns_per_table": schema.Int64Attribute{
+			Computed:    true,
+			Description: "The maximum number of columns per table for the cluster database.",
+		},
+		"retention_period": schema.Int64Attribute{
+			Computed:    true,
+			Description: "The retention period of the cluster database in nanoseconds.",
+		},
+		"partition_template": schema.ListNestedAttribute{
+			Computed:    true,
+			Description: "The template partitioning of the cluster database.",
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"type": schema.StringAttribute{
+						Computed:    true,
+						Description: "The type of template part.",
+					},
+					"value": schema.StringAttribute{
+						Computed:    true,
+						Description: "The value of template part.",
 					},
 				},
 			},
@@ -115,13 +147,33 @@ func (d *DatabasesDataSource) Configure(ctx context.Context, req datasource.Conf
 	d.accountID = pd.accountID
 	d.client = pd.client
 	d.clusterID = pd.clusterID
+	d.databaseCache = pd.databaseCache
+	d.namespace = pd.namespace
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (d *DatabasesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state DatabasesDataSourceModel
 
-	readDatabasesResponse, err := d.client.GetClusterDatabasesWithResponse(ctx, d.accountID, d.clusterID)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var nameRegex *regexp.Regexp
+	if !state.NameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(state.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error getting databases",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	readDatabasesResponse, err := d.databaseCache.list(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error getting databases",
@@ -147,7 +199,18 @@ func (d *DatabasesDataSource) Read(ctx context.Context, req datasource.ReadReque
 	}
 
 	// Map response body to model
+	state.Databases = []DatabaseModel{}
+	state.DatabasesByName = map[string]DatabaseModel{}
+	state.Names = []types.String{}
 	for _, database := range *readDatabasesResponse.JSON200 {
+		name, ok := d.namespace.unqualify(database.Name)
+		if !ok {
+			continue
+		}
+		if nameRegex != nil && !nameRegex.MatchString(name) {
+			continue
+		}
+
 		partitionTemplate, err := getPartitionTemplate(database.PartitionTemplate)
 		if err != nil {
 			resp.Diagnostics.AddError(
@@ -162,11 +225,13 @@ func (d *DatabasesDataSource) Read(ctx context.Context, req datasource.ReadReque
 			ClusterId:          types.StringValue(database.ClusterId.String()),
 			MaxTables:          types.Int64Value(int64(database.MaxTables)),
 			MaxColumnsPerTable: types.Int64Value(int64(database.MaxColumnsPerTable)),
-			Name:               types.StringValue(database.Name),
+			Name:               types.StringValue(name),
 			PartitionTemplate:  partitionTemplate,
 			RetentionPeriod:    types.Int64Value(database.RetentionPeriod),
 		}
 		state.Databases = append(state.Databases, databaseState)
+		state.DatabasesByName[name] = databaseState
+		state.Names = append(state.Names, databaseState.Name)
 	}
 
 	// Set state
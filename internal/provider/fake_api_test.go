@@ -0,0 +1,229 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/thulasirajkomminar/influxdb3-management-go"
+)
+
+// fakeAPI is an in-memory fake of the databases and database tokens of a
+// cluster in the InfluxDB V3 management API, for unit tests of resources.
+type fakeAPI struct {
+	accountID influxdb3.UuidV4
+	clusterID influxdb3.UuidV4
+	client    influxdb3.ClientWithResponses
+	url       string
+
+	// intercept, when set, is called before a request is served with the
+	// resource path. It returns false after writing its own response instead.
+	intercept func(w http.ResponseWriter, r *http.Request, resource string) bool
+
+	mu        sync.Mutex
+	databases []map[string]any
+	tokens    []map[string]any
+	requests  []string
+}
+
+func newFakeAPI(t *testing.T) *fakeAPI {
+	t.Helper()
+
+	api := &fakeAPI{
+		accountID: uuid.New(),
+		clusterID: uuid.New(),
+	}
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	client, err := influxdb3.NewClientWithResponses(server.URL, influxdb3.WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("unable to create client: %s", err)
+	}
+	api.client = *client
+	api.url = server.URL
+	return api
+}
+
+// retryingClient returns a client that retries failed requests and counts
+// their attempts, as the client of the provider does.
+func (api *fakeAPI) retryingClient(t *testing.T) influxdb3.ClientWithResponses {
+	t.Helper()
+
+	retryClient := retryablehttp.NewClient()
+	retryClient.Logger = nil
+	retryClient.RetryWaitMin = time.Millisecond
+	retryClient.RetryWaitMax = time.Millisecond
+	retryClient.RequestLogHook = countRequestAttempt
+
+	client, err := influxdb3.NewClientWithResponses(api.url, influxdb3.WithHTTPClient(retryClient.StandardClient()))
+	if err != nil {
+		t.Fatalf("unable to create client: %s", err)
+	}
+	return *client
+}
+
+// addDatabase adds a database to the cluster.
+func (api *fakeAPI) addDatabase(database map[string]any) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	api.databases = append(api.databases, api.withIDs(database))
+}
+
+// addToken adds a database token to the cluster and returns its ID.
+func (api *fakeAPI) addToken(token map[string]any) string {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	id := uuid.NewString()
+	token = api.withIDs(token)
+	token["id"] = id
+	token["createdAt"] = time.Now().UTC().Format(time.RFC3339Nano)
+	api.tokens = append(api.tokens, token)
+	return id
+}
+
+// tokenList returns the database tokens of the cluster.
+func (api *fakeAPI) tokenList() []map[string]any {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	return append([]map[string]any{}, api.tokens...)
+}
+
+// requestLog returns the method and path of the requests served so far,
+// without the account and cluster prefix.
+func (api *fakeAPI) requestLog() []string {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	return append([]string{}, api.requests...)
+}
+
+func (api *fakeAPI) withIDs(object map[string]any) map[string]any {
+	copied := map[string]any{
+		"accountId": api.accountID.String(),
+		"clusterId": api.clusterID.String(),
+	}
+	for key, value := range object {
+		copied[key] = value
+	}
+	return copied
+}
+
+func (api *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	prefix := fmt.Sprintf("/accounts/%s/clusters/%s", api.accountID, api.clusterID)
+	resource, ok := strings.CutPrefix(r.URL.Path, prefix)
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "cluster not found")
+		return
+	}
+
+	api.mu.Lock()
+	api.requests = append(api.requests, r.Method+" "+resource)
+	api.mu.Unlock()
+
+	if api.intercept != nil && !api.intercept(w, r, resource) {
+		return
+	}
+	api.serve(w, r, resource)
+}
+
+// serve serves the request for the resource path without the account and
+// cluster prefix. Intercepts call it to serve a request before failing it.
+func (api *fakeAPI) serve(w http.ResponseWriter, r *http.Request, resource string) {
+	var body map[string]any
+	if r.Body != nil {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	collection, name, _ := strings.Cut(strings.TrimPrefix(resource, "/"), "/")
+	objects, key := &api.databases, "name"
+	if collection == "tokens" {
+		objects, key = &api.tokens, "id"
+	}
+
+	index := -1
+	for i, object := range *objects {
+		if name != "" && object[key] == name {
+			index = i
+		}
+	}
+
+	switch {
+	case name == "" && r.Method == http.MethodGet:
+		list := []map[string]any{}
+		for _, object := range *objects {
+			list = append(list, withoutAccessToken(object))
+		}
+		writeFakeJSON(w, http.StatusOK, list)
+	case name == "" && r.Method == http.MethodPost:
+		if collection == "databases" {
+			for _, database := range api.databases {
+				if database["name"] == body["name"] {
+					writeFakeError(w, http.StatusConflict, "database already exists")
+					return
+				}
+			}
+			body = api.withIDs(body)
+			for attribute, value := range map[string]any{"maxTables": 500, "maxColumnsPerTable": 200, "retentionPeriod": 0} {
+				if _, ok := body[attribute]; !ok {
+					body[attribute] = value
+				}
+			}
+		} else {
+			body = api.withIDs(body)
+			body["id"] = uuid.NewString()
+			body["accessToken"] = "apiv1_" + uuid.NewString()
+			body["createdAt"] = time.Now().UTC().Format(time.RFC3339Nano)
+		}
+		*objects = append(*objects, body)
+		writeFakeJSON(w, http.StatusOK, body)
+	case index < 0:
+		writeFakeError(w, http.StatusNotFound, collection+" not found")
+	case r.Method == http.MethodGet:
+		writeFakeJSON(w, http.StatusOK, withoutAccessToken((*objects)[index]))
+	case r.Method == http.MethodPatch:
+		for attribute, value := range body {
+			(*objects)[index][attribute] = value
+		}
+		writeFakeJSON(w, http.StatusOK, withoutAccessToken((*objects)[index]))
+	case r.Method == http.MethodDelete:
+		*objects = append((*objects)[:index], (*objects)[index+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func withoutAccessToken(object map[string]any) map[string]any {
+	copied := map[string]any{}
+	for key, value := range object {
+		if key != "accessToken" {
+			copied[key] = value
+		}
+	}
+	return copied
+}
+
+func writeFakeJSON(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeFakeError(w http.ResponseWriter, statusCode int, message string) {
+	writeFakeJSON(w, statusCode, map[string]any{"code": statusCode, "message": message})
+}
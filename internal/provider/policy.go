@@ -0,0 +1,260 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// PolicyModel maps the provider policy block schema data.
+type PolicyModel struct {
+	DatabaseNameRegex   types.String `tfsdk:"database_name_regex"`
+	ForbidWildcardWrite types.Bool   `tfsdk:"forbid_wildcard_write"`
+	MaxColumnsPerTable  types.Int64  `tfsdk:"max_columns_per_table"`
+	MaxRetentionPeriod  types.Int64  `tfsdk:"max_retention_period"`
+	MaxTables           types.Int64  `tfsdk:"max_tables"`
+	MaxTokenLifetime    types.String `tfsdk:"max_token_lifetime"`
+	RequireTokenExpiry  types.Bool   `tfsdk:"require_token_expiry"`
+}
+
+// policyBlock returns the schema of the provider policy block.
+func policyBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "Organisation rules that database tokens and databases must follow. The rules are checked while planning, and plans that violate a rule fail with an error naming the rule.",
+		Attributes: map[string]schema.Attribute{
+			"database_name_regex": schema.StringAttribute{
+				Description: "A regular expression the names of databases must match.",
+				Optional:    true,
+				Validators: []validator.String{
+					regexpValidator{},
+				},
+			},
+			"forbid_wildcard_write": schema.BoolAttribute{
+				Description: "Whether database tokens with write permission on all databases (`*`) are forbidden.",
+				Optional:    true,
+			},
+			"max_columns_per_table": schema.Int64Attribute{
+				Description: "The maximum `max_columns_per_table` of databases.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_retention_period": schema.Int64Attribute{
+				Description: "The maximum `retention_period` of databases in nanoseconds. Databases with an infinite retention period (`0`) are forbidden.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_tables": schema.Int64Attribute{
+				Description: "The maximum `max_tables` of databases.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_token_lifetime": schema.StringAttribute{
+				Description: "The maximum lifetime of database tokens (for example: `2160h`), measured from the creation of the token. Implies `require_token_expiry`.",
+				Optional:    true,
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+			"require_token_expiry": schema.BoolAttribute{
+				Description: "Whether database tokens must set `expires_at` or `expires_in`.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// providerPolicy holds the parsed provider policy rules. Resources enforce
+// them in ModifyPlan rather than ValidateConfig, as the provider is not
+// configured yet while Terraform validates the configuration.
+type providerPolicy struct {
+	databaseNameRegex   *regexp.Regexp
+	forbidWildcardWrite bool
+	maxColumnsPerTable  int64
+	maxRetentionPeriod  int64
+	maxTables           int64
+	maxTokenLifetime    time.Duration
+	requireTokenExpiry  bool
+}
+
+// newProviderPolicy parses the policy block of the provider configuration.
+// It returns nil when the block is not set.
+func newProviderPolicy(m *PolicyModel) (*providerPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if m == nil {
+		return nil, diags
+	}
+
+	policy := &providerPolicy{
+		forbidWildcardWrite: m.ForbidWildcardWrite.ValueBool(),
+		maxColumnsPerTable:  m.MaxColumnsPerTable.ValueInt64(),
+		maxRetentionPeriod:  m.MaxRetentionPeriod.ValueInt64(),
+		maxTables:           m.MaxTables.ValueInt64(),
+		requireTokenExpiry:  m.RequireTokenExpiry.ValueBool(),
+	}
+
+	if !m.DatabaseNameRegex.IsNull() {
+		databaseNameRegex, err := regexp.Compile(m.DatabaseNameRegex.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("policy").AtName("database_name_regex"),
+				"Invalid Database Name Regex",
+				"The provider cannot parse the database name regex. Error: "+err.Error(),
+			)
+		}
+		policy.databaseNameRegex = databaseNameRegex
+	}
+
+	if !m.MaxTokenLifetime.IsNull() {
+		maxTokenLifetime, err := time.ParseDuration(m.MaxTokenLifetime.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("policy").AtName("max_token_lifetime"),
+				"Invalid Max Token Lifetime",
+				"The provider cannot parse the max token lifetime as a duration. "+
+					"Set the value to a duration such as 2160h. Error: "+err.Error(),
+			)
+		}
+		policy.maxTokenLifetime = maxTokenLifetime
+		policy.requireTokenExpiry = true
+	}
+
+	return policy, diags
+}
+
+// validateToken checks the database token against the policy rules. Unknown
+// values are skipped so they are checked once they are known. The lifetime
+// of a token with a known created_at is measured from it.
+func (p *providerPolicy) validateToken(token TokenResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if p == nil {
+		return diags
+	}
+
+	if p.forbidWildcardWrite {
+		permissions, _ := tokenPermissions(token.Permissions)
+		for _, permission := range permissions {
+			if permission.Action.ValueString() == "write" && permission.Resource.ValueString() == "*" {
+				diags.AddAttributeError(
+					path.Root("permissions"),
+					"Provider Policy Violation",
+					"The provider policy rule \"forbid_wildcard_write\" forbids database tokens with write permission on all databases (\"*\").",
+				)
+				break
+			}
+		}
+	}
+
+	if p.requireTokenExpiry && token.ExpiresAt.IsNull() && token.ExpiresIn.IsNull() {
+		rule := "require_token_expiry"
+		if p.maxTokenLifetime > 0 {
+			rule = "max_token_lifetime"
+		}
+		diags.AddAttributeError(
+			path.Root("expires_at"),
+			"Provider Policy Violation",
+			fmt.Sprintf("The provider policy rule %q requires database tokens to set expires_at or expires_in.", rule),
+		)
+		return diags
+	}
+
+	if p.maxTokenLifetime <= 0 {
+		return diags
+	}
+
+	// Prefer the expiry of the token and fall back to expires_in for tokens
+	// that do not know their expiry yet. The lifetime is measured from the
+	// creation of the token, or from now for a token that is not created yet.
+	var lifetime time.Duration
+	switch {
+	case !token.ExpiresAt.IsNull() && !token.ExpiresAt.IsUnknown():
+		expiry, err := time.Parse(time.RFC3339, token.ExpiresAt.ValueString())
+		if err != nil {
+			return diags
+		}
+		createdAt := time.Now()
+		if !token.CreatedAt.IsNull() && !token.CreatedAt.IsUnknown() {
+			createdAt, err = time.Parse(time.RFC3339, token.CreatedAt.ValueString())
+			if err != nil {
+				return diags
+			}
+		}
+		lifetime = expiry.Sub(createdAt)
+	case !token.ExpiresIn.IsNull() && !token.ExpiresIn.IsUnknown():
+		expiresIn, err := time.ParseDuration(token.ExpiresIn.ValueString())
+		if err != nil {
+			return diags
+		}
+		lifetime = expiresIn
+	default:
+		return diags
+	}
+
+	if lifetime > p.maxTokenLifetime {
+		diags.AddAttributeError(
+			path.Root("expires_at"),
+			"Provider Policy Violation",
+			fmt.Sprintf("The provider policy rule \"max_token_lifetime\" limits the lifetime of database tokens to %s, but the database token %q would live for %s.", p.maxTokenLifetime, token.Description.ValueString(), lifetime.Truncate(time.Second)),
+		)
+	}
+
+	return diags
+}
+
+// validateDatabase checks the database against the policy rules. Unknown
+// values are skipped so they are checked once they are known.
+func (p *providerPolicy) validateDatabase(database DatabaseModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if p == nil {
+		return diags
+	}
+
+	if p.databaseNameRegex != nil && !database.Name.IsNull() && !database.Name.IsUnknown() && !p.databaseNameRegex.MatchString(database.Name.ValueString()) {
+		diags.AddAttributeError(
+			path.Root("name"),
+			"Provider Policy Violation",
+			fmt.Sprintf("The provider policy rule \"database_name_regex\" requires database names to match %q, but the database name is %q.", p.databaseNameRegex.String(), database.Name.ValueString()),
+		)
+	}
+
+	if p.maxRetentionPeriod > 0 && !database.RetentionPeriod.IsNull() && !database.RetentionPeriod.IsUnknown() {
+		retentionPeriod := database.RetentionPeriod.ValueInt64()
+		if retentionPeriod == 0 || retentionPeriod > p.maxRetentionPeriod {
+			diags.AddAttributeError(
+				path.Root("retention_period"),
+				"Provider Policy Violation",
+				fmt.Sprintf("The provider policy rule \"max_retention_period\" limits the retention period of databases to %d nanoseconds, but the retention period is %d (0 is infinite).", p.maxRetentionPeriod, retentionPeriod),
+			)
+		}
+	}
+
+	if p.maxTables > 0 && !database.MaxTables.IsNull() && !database.MaxTables.IsUnknown() && database.MaxTables.ValueInt64() > p.maxTables {
+		diags.AddAttributeError(
+			path.Root("max_tables"),
+			"Provider Policy Violation",
+			fmt.Sprintf("The provider policy rule \"max_tables\" limits max_tables of databases to %d, but max_tables is %d.", p.maxTables, database.MaxTables.ValueInt64()),
+		)
+	}
+
+	if p.maxColumnsPerTable > 0 && !database.MaxColumnsPerTable.IsNull() && !database.MaxColumnsPerTable.IsUnknown() && database.MaxColumnsPerTable.ValueInt64() > p.maxColumnsPerTable {
+		diags.AddAttributeError(
+			path.Root("max_columns_per_table"),
+			"Provider Policy Violation",
+			fmt.Sprintf("The provider policy rule \"max_columns_per_table\" limits max_columns_per_table of databases to %d, but max_columns_per_table is %d.", p.maxColumnsPerTable, database.MaxColumnsPerTable.ValueInt64()),
+		)
+	}
+
+	return diags
+}
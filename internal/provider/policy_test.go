@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestProviderPolicyValidateToken(t *testing.T) {
+	policy, diags := newProviderPolicy(&PolicyModel{
+		ForbidWildcardWrite: types.BoolValue(true),
+		MaxTokenLifetime:    types.StringValue("720h"),
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	readSignals := permissionsValue([]TokenPermissionModel{{Action: types.StringValue("read"), Resource: types.StringValue("signals")}})
+	testCases := map[string]struct {
+		token         TokenResourceModel
+		expectedError bool
+	}{
+		"valid": {
+			token: TokenResourceModel{Permissions: readSignals, ExpiresIn: types.StringValue("168h")},
+		},
+		"wildcard-write": {
+			token: TokenResourceModel{
+				Permissions: permissionsValue([]TokenPermissionModel{{Action: types.StringValue("write"), Resource: types.StringValue("*")}}),
+				ExpiresIn:   types.StringValue("168h"),
+			},
+			expectedError: true,
+		},
+		"no-expiry": {
+			token:         TokenResourceModel{Permissions: readSignals},
+			expectedError: true,
+		},
+		"unknown-expiry": {
+			token: TokenResourceModel{Permissions: readSignals, ExpiresAt: types.StringUnknown()},
+		},
+		"expires-in-too-long": {
+			token:         TokenResourceModel{Permissions: readSignals, ExpiresIn: types.StringValue("1000h")},
+			expectedError: true,
+		},
+		"expires-at-too-long": {
+			token:         TokenResourceModel{Permissions: readSignals, ExpiresAt: types.StringValue(time.Now().Add(1000 * time.Hour).Format(time.RFC3339))},
+			expectedError: true,
+		},
+		"created-at-within-lifetime": {
+			token: TokenResourceModel{
+				Permissions: readSignals,
+				CreatedAt:   types.StringValue(time.Now().Add(-100 * time.Hour).Format(time.RFC3339Nano)),
+				ExpiresAt:   types.StringValue(time.Now().Add(500 * time.Hour).Format(time.RFC3339)),
+			},
+		},
+		"created-at-too-long": {
+			token: TokenResourceModel{
+				Permissions: readSignals,
+				CreatedAt:   types.StringValue(time.Now().Add(-500 * time.Hour).Format(time.RFC3339Nano)),
+				ExpiresAt:   types.StringValue(time.Now().Add(400 * time.Hour).Format(time.RFC3339)),
+			},
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			diags := policy.validateToken(testCase.token)
+			if diags.HasError() != testCase.expectedError {
+				t.Errorf("expected error %t, got diagnostics: %v", testCase.expectedError, diags)
+			}
+		})
+	}
+}
+
+func TestProviderPolicyValidateDatabase(t *testing.T) {
+	policy, diags := newProviderPolicy(&PolicyModel{
+		DatabaseNameRegex:  types.StringValue("^team_"),
+		MaxColumnsPerTable: types.Int64Value(200),
+		MaxRetentionPeriod: types.Int64Value(int64(30 * 24 * time.Hour)),
+		MaxTables:          types.Int64Value(500),
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	valid := DatabaseModel{
+		Name:               types.StringValue("team_signals"),
+		MaxColumnsPerTable: types.Int64Value(200),
+		MaxTables:          types.Int64Value(500),
+		RetentionPeriod:    types.Int64Value(int64(7 * 24 * time.Hour)),
+	}
+	testCases := map[string]struct {
+		modify        func(*DatabaseModel)
+		expectedError bool
+	}{
+		"valid": {
+			modify: func(*DatabaseModel) {},
+		},
+		"name": {
+			modify:        func(m *DatabaseModel) { m.Name = types.StringValue("signals") },
+			expectedError: true,
+		},
+		"unknown-name": {
+			modify: func(m *DatabaseModel) { m.Name = types.StringUnknown() },
+		},
+		"infinite-retention": {
+			modify:        func(m *DatabaseModel) { m.RetentionPeriod = types.Int64Value(0) },
+			expectedError: true,
+		},
+		"max-tables": {
+			modify:        func(m *DatabaseModel) { m.MaxTables = types.Int64Value(501) },
+			expectedError: true,
+		},
+		"max-columns-per-table": {
+			modify:        func(m *DatabaseModel) { m.MaxColumnsPerTable = types.Int64Value(201) },
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			database := valid
+			testCase.modify(&database)
+
+			diags := policy.validateDatabase(database)
+			if diags.HasError() != testCase.expectedError {
+				t.Errorf("expected error %t, got diagnostics: %v", testCase.expectedError, diags)
+			}
+		})
+	}
+}
@@ -4,15 +4,20 @@ import (
 	"context"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/thulasirajkomminar/influxdb3-management-go"
@@ -20,9 +25,11 @@ import (
 
 // INFLUXDB3_HOST is the default InfluxDB V3 API host.
 // INFLUXDB3_API_ENDPOINT is the default InfluxDB V3 API endpoint.
+// INFLUXDB3_LEGACY_PROVIDER is the registry namespace and type the provider was published under before v1.4.0.
 const (
-	INFLUXDB3_HOST         = "https://console.influxdata.com"
-	INFLUXDB3_API_ENDPOINT = "/api/v0"
+	INFLUXDB3_HOST            = "https://console.influxdata.com"
+	INFLUXDB3_API_ENDPOINT    = "/api/v0"
+	INFLUXDB3_LEGACY_PROVIDER = "komminarlabs/influxdb3"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -38,15 +45,33 @@ type InfluxDBProvider struct {
 
 // InfluxDBProviderModel maps provider schema data to a Go type.
 type InfluxDBProviderModel struct {
-	AccountID types.String `tfsdk:"account_id"`
-	ClusterID types.String `tfsdk:"cluster_id"`
-	Token     types.String `tfsdk:"token"`
+	AccountID                 types.String           `tfsdk:"account_id"`
+	AuditLogPath              types.String           `tfsdk:"audit_log_path"`
+	ClusterID                 types.String           `tfsdk:"cluster_id"`
+	DatabaseDefaults          *DatabaseDefaultsModel `tfsdk:"database_defaults"`
+	DatabaseNamePrefix        types.String           `tfsdk:"database_name_prefix"`
+	ExpiryWarningWindow       types.String           `tfsdk:"expiry_warning_window"`
+	MaxConcurrentRequests     types.Int64            `tfsdk:"max_concurrent_requests"`
+	Policy                    *PolicyModel           `tfsdk:"policy"`
+	ReadOnly                  types.Bool             `tfsdk:"read_only"`
+	RequestsPerSecond         types.Float64          `tfsdk:"requests_per_second"`
+	SkipCredentialsValidation types.Bool             `tfsdk:"skip_credentials_validation"`
+	StrictPermissions         types.Bool             `tfsdk:"strict_permissions"`
+	Token                     types.String           `tfsdk:"token"`
 }
 
 type providerData struct {
-	accountID influxdb3.UuidV4
-	client    influxdb3.ClientWithResponses
-	clusterID influxdb3.UuidV4
+	accountID           influxdb3.UuidV4
+	auditLog            *auditLog
+	client              influxdb3.ClientWithResponses
+	clusterID           influxdb3.UuidV4
+	databaseCache       *databaseCache
+	databaseDefaults    *databaseDefaults
+	expiryWarningWindow time.Duration
+	namespace           databaseNamespace
+	policy              *providerPolicy
+	readOnly            bool
+	strictPermissions   bool
 }
 
 // Metadata returns the provider type name.
@@ -66,17 +91,65 @@ func (p *InfluxDBProvider) Schema(ctx context.Context, req provider.SchemaReques
 				Optional:    true,
 				Sensitive:   true,
 			},
+			"audit_log_path": schema.StringAttribute{
+				Description: "The path of a local file to which every create, update and delete of a database or database token appends a JSON line. Each line records the timestamp, operation, account and cluster, database name or token ID or description, request body with secrets redacted, response status and Terraform workspace, along with the SHA-256 hash of the previous line so that changes to the file can be detected.",
+				Optional:    true,
+			},
 			"cluster_id": schema.StringAttribute{
 				Description: "The ID of the cluster that you want to manage",
 				Optional:    true,
 				Sensitive:   true,
 			},
+			"database_name_prefix": schema.StringAttribute{
+				Description: "A prefix for the names of all databases managed or read by the provider. Database names in resources, token permissions and data sources leave out the prefix, which the provider adds before sending them to the cluster and strips from the names it reads. Data sources only return databases, and database tokens on databases, with the prefix. Database tokens with permissions on all databases (`*`) cannot be managed.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"expiry_warning_window": schema.StringAttribute{
+				Description: "The duration before a database token expires within which plans warn about the upcoming expiry (for example: `336h`). Plans always fail for database tokens that have already expired.",
+				Optional:    true,
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Description: "The maximum number of requests to the management API in flight at once, shared by all resources and data sources of the provider. Each retry of a request counts as a request. By default the number is unlimited.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"read_only": schema.BoolAttribute{
+				Description: "Whether the provider refuses to create, update or delete anything, so it can only read from the cluster. Can also be set with the `INFLUXDB3_READ_ONLY` environment variable. The default is `false`.",
+				Optional:    true,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Description: "The maximum number of requests per second to the management API, shared by all resources and data sources of the provider. Each retry of a request counts as a request. The minimum is `0.01`. By default the rate is unlimited.",
+				Optional:    true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0.01),
+				},
+			},
+			"skip_credentials_validation": schema.BoolAttribute{
+				Description: "Whether the provider skips checking, while it is configured, that the management token can list the databases of the cluster. The check turns a wrong token, account or cluster into a single error. The default is `false`.",
+				Optional:    true,
+			},
+			"strict_permissions": schema.BoolAttribute{
+				Description: "Whether a permission naming a database that does not exist in the cluster fails the plan of a database token, instead of only warning. Database names only known during apply are checked when the database token is created or updated. The default is `false`.",
+				Optional:    true,
+			},
 			"token": schema.StringAttribute{
 				Description: "The InfluxDB management token",
 				Optional:    true,
 				Sensitive:   true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"database_defaults": databaseDefaultsBlock(),
+			"policy":            policyBlock(),
+		},
 	}
 }
 
@@ -131,6 +204,20 @@ func (p *InfluxDBProvider) Configure(ctx context.Context, req provider.Configure
 	clusterID := os.Getenv("INFLUXDB3_CLUSTER_ID")
 	token := os.Getenv("INFLUXDB3_TOKEN")
 
+	readOnly := false
+	if v := os.Getenv("INFLUXDB3_READ_ONLY"); v != "" {
+		var err error
+		readOnly, err = strconv.ParseBool(v)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid INFLUXDB3_READ_ONLY",
+				"The provider cannot parse the INFLUXDB3_READ_ONLY environment variable as a boolean. "+
+					"Set the value to true or false. Error: "+err.Error(),
+			)
+			return
+		}
+	}
+
 	if !config.AccountID.IsNull() {
 		accountID = config.AccountID.ValueString()
 	}
@@ -143,6 +230,10 @@ func (p *InfluxDBProvider) Configure(ctx context.Context, req provider.Configure
 		token = config.Token.ValueString()
 	}
 
+	if !config.ReadOnly.IsNull() {
+		readOnly = config.ReadOnly.ValueBool()
+	}
+
 	// Combine host and endpoint
 	url := INFLUXDB3_HOST + INFLUXDB3_API_ENDPOINT
 
@@ -212,10 +303,43 @@ func (p *InfluxDBProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
+	var expiryWarningWindow time.Duration
+	if !config.ExpiryWarningWindow.IsNull() && !config.ExpiryWarningWindow.IsUnknown() {
+		expiryWarningWindow, err = time.ParseDuration(config.ExpiryWarningWindow.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("expiry_warning_window"),
+				"Invalid Expiry Warning Window",
+				"The provider cannot parse the expiry warning window as a duration. "+
+					"Set the value to a duration such as 336h. Error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	policy, diags := newProviderPolicy(config.Policy)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	databaseDefaults := newDatabaseDefaults(config.DatabaseDefaults)
+
+	auditLog, err := newAuditLog(config.AuditLogPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("audit_log_path"),
+			"Unable to Open Audit Log",
+			"The provider cannot write to the audit log. Error: "+err.Error(),
+		)
+		return
+	}
+
 	ctx = tflog.SetField(ctx, "INFLUXDB3_ACCOUNT_ID", accountID)
 	ctx = tflog.SetField(ctx, "INFLUXDB3_CLUSTER_ID", clusterID)
 	ctx = tflog.SetField(ctx, "INFLUXDB3_TOKEN", token)
 	ctx = tflog.SetField(ctx, "INFLUXDB3_URL", url)
+	ctx = tflog.SetField(ctx, "INFLUXDB3_READ_ONLY", readOnly)
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "INFLUXDB3_TOKEN")
 
 	tflog.Debug(ctx, "Creating InfluxDB V3 client")
@@ -228,12 +352,21 @@ func (p *InfluxDBProvider) Configure(ctx context.Context, req provider.Configure
 	retryClient.RetryWaitMin = 1 * time.Second
 	retryClient.RetryWaitMax = 5 * time.Second
 	retryClient.RetryMax = 3
+	retryClient.RequestLogHook = countRequestAttempt
+	if config.MaxConcurrentRequests.ValueInt64() > 0 || config.RequestsPerSecond.ValueFloat64() > 0 {
+		retryClient.HTTPClient.Transport = newLimitTransport(retryClient.HTTPClient.Transport, config.MaxConcurrentRequests.ValueInt64(), config.RequestsPerSecond.ValueFloat64())
+	}
+
+	httpClient := retryClient.StandardClient()
+	if readOnly {
+		httpClient.Transport = &readOnlyTransport{next: httpClient.Transport}
+	}
 
 	client, err := influxdb3.NewClientWithResponses(url, influxdb3.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
 		req.Header.Set("Accept", "application/json")
 		req.Header.Set("Authorization", "Bearer "+token)
 		return nil
-	}), influxdb3.WithHTTPClient(retryClient.StandardClient()))
+	}), influxdb3.WithHTTPClient(httpClient))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create InfluxDB V3 Client",
@@ -244,13 +377,29 @@ func (p *InfluxDBProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
+	databaseCache := newDatabaseCache(*client, accountUUID, clusterUUID)
+	if !config.SkipCredentialsValidation.ValueBool() {
+		resp.Diagnostics.Append(validateCredentials(ctx, databaseCache, accountUUID, clusterUUID)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	// Make the InfluxDB client available during DataSource and Resource
 	// type Configure methods.
 
 	providerData := &providerData{
-		accountID: accountUUID,
-		client:    *client,
-		clusterID: clusterUUID,
+		accountID:           accountUUID,
+		auditLog:            auditLog,
+		client:              *client,
+		clusterID:           clusterUUID,
+		databaseCache:       databaseCache,
+		databaseDefaults:    databaseDefaults,
+		expiryWarningWindow: expiryWarningWindow,
+		namespace:           databaseNamespace(config.DatabaseNamePrefix.ValueString()),
+		policy:              policy,
+		readOnly:            readOnly,
+		strictPermissions:   config.StrictPermissions.ValueBool(),
 	}
 	resp.DataSourceData = *providerData
 	resp.ResourceData = *providerData
@@ -272,6 +421,8 @@ func (p *InfluxDBProvider) DataSources(ctx context.Context) []func() datasource.
 		NewTokensDataSource,
 		NewDatabaseDataSource,
 		NewDatabasesDataSource,
+		NewDatabaseAccessDataSource,
+		NewTokenPermissionsDataSource,
 	}
 }
 
@@ -2,10 +2,15 @@ package provider
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 const (
@@ -35,3 +40,177 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("INFLUXDB3_TOKEN must be set for acceptance tests")
 	}
 }
+
+// testUpgradeResourceState upgrades a recorded state fixture from the testdata
+// directory and returns it decoded with the current schema of the resource.
+func testUpgradeResourceState(t *testing.T, r resource.Resource, typeName string, version int64, fixture string) tfsdk.State {
+	t.Helper()
+
+	resp, err := providerserver.NewProtocol6(New("test")())().UpgradeResourceState(t.Context(), &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: typeName,
+		Version:  version,
+		RawState: testRawState(t, fixture),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error upgrading %s state: %s", typeName, err)
+	}
+	testCheckDiagnostics(t, resp.Diagnostics)
+
+	return testResourceState(t, r, resp.UpgradedState)
+}
+
+// testMoveResourceState moves a recorded state fixture from the testdata
+// directory and returns it decoded with the current schema of the resource.
+func testMoveResourceState(t *testing.T, r resource.Resource, sourceProviderAddress string, typeName string, fixture string) tfsdk.State {
+	t.Helper()
+
+	resp, err := providerserver.NewProtocol6(New("test")())().MoveResourceState(t.Context(), &tfprotov6.MoveResourceStateRequest{
+		SourceProviderAddress: sourceProviderAddress,
+		SourceSchemaVersion:   0,
+		SourceState:           testRawState(t, fixture),
+		SourceTypeName:        typeName,
+		TargetTypeName:        typeName,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error moving %s state: %s", typeName, err)
+	}
+	testCheckDiagnostics(t, resp.Diagnostics)
+
+	return testResourceState(t, r, resp.TargetState)
+}
+
+func testRawState(t *testing.T, fixture string) *tfprotov6.RawState {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatalf("unable to read fixture %s: %s", fixture, err)
+	}
+	return &tfprotov6.RawState{JSON: b}
+}
+
+func testResourceState(t *testing.T, r resource.Resource, value *tfprotov6.DynamicValue) tfsdk.State {
+	t.Helper()
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(t.Context(), resource.SchemaRequest{}, schemaResp)
+
+	if value == nil {
+		t.Fatal("expected state, got none")
+	}
+
+	raw, err := value.Unmarshal(schemaResp.Schema.Type().TerraformType(t.Context()))
+	if err != nil {
+		t.Fatalf("unable to decode state: %s", err)
+	}
+	return tfsdk.State{Schema: schemaResp.Schema, Raw: raw}
+}
+
+func testCheckDiagnostics(t *testing.T, diagnostics []*tfprotov6.Diagnostic) {
+	t.Helper()
+
+	for _, d := range diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("unexpected error diagnostic: %s: %s", d.Summary, d.Detail)
+		}
+	}
+}
+
+// testModifyPlan runs ModifyPlan of the resource for a configuration and prior
+// state given as attribute values by name. A nil state plans a create. Like
+// Terraform, the proposed new state takes attributes the configuration leaves
+// unset from the prior state, and leaves them unknown when they are computed
+// and the resource is created.
+func testModifyPlan(t *testing.T, r resource.ResourceWithModifyPlan, config map[string]tftypes.Value, state map[string]tftypes.Value) *resource.ModifyPlanResponse {
+	t.Helper()
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(t.Context(), resource.SchemaRequest{}, schemaResp)
+	objectType, ok := schemaResp.Schema.Type().TerraformType(t.Context()).(tftypes.Object)
+	if !ok {
+		t.Fatalf("expected an object schema, got %s", schemaResp.Schema.Type())
+	}
+
+	configValues := map[string]tftypes.Value{}
+	stateValues := map[string]tftypes.Value{}
+	proposedValues := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		configValues[name] = tftypes.NewValue(attributeType, nil)
+		if value, ok := config[name]; ok {
+			configValues[name] = value
+		}
+		stateValues[name] = tftypes.NewValue(attributeType, nil)
+		if value, ok := state[name]; ok {
+			stateValues[name] = value
+		}
+
+		switch {
+		case !configValues[name].IsNull():
+			proposedValues[name] = configValues[name]
+		case state != nil:
+			proposedValues[name] = stateValues[name]
+		case schemaResp.Schema.Attributes[name].IsComputed():
+			proposedValues[name] = tftypes.NewValue(attributeType, tftypes.UnknownValue)
+		default:
+			proposedValues[name] = configValues[name]
+		}
+	}
+
+	req := resource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, configValues)},
+		Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, proposedValues)},
+		State:  tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)},
+	}
+	if state != nil {
+		req.State.Raw = tftypes.NewValue(objectType, stateValues)
+	}
+	resp := &resource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(t.Context(), req, resp)
+	return resp
+}
+
+// testResourceObject returns a state of the resource with the given attribute
+// values, and null values for the other attributes.
+func testResourceObject(t *testing.T, r resource.Resource, values map[string]tftypes.Value) tfsdk.State {
+	t.Helper()
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(t.Context(), resource.SchemaRequest{}, schemaResp)
+	objectType, ok := schemaResp.Schema.Type().TerraformType(t.Context()).(tftypes.Object)
+	if !ok {
+		t.Fatalf("expected an object schema, got %s", schemaResp.Schema.Type())
+	}
+
+	stateValues := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		stateValues[name] = tftypes.NewValue(attributeType, nil)
+		if value, ok := values[name]; ok {
+			stateValues[name] = value
+		}
+	}
+	return tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, stateValues)}
+}
+
+// testCheckDiagnosticSummaries checks that the diagnostics hold an error and a
+// warning with the expected summaries, and no other errors. Empty summaries
+// expect no error or warning.
+func testCheckDiagnosticSummaries(t *testing.T, diags diag.Diagnostics, expectedError string, expectedWarning string) {
+	t.Helper()
+
+	for severity, expected := range map[diag.Severity]string{diag.SeverityError: expectedError, diag.SeverityWarning: expectedWarning} {
+		found := false
+		for _, d := range diags {
+			if d.Severity() != severity {
+				continue
+			}
+			if d.Summary() == expected {
+				found = true
+			} else if severity == diag.SeverityError {
+				t.Errorf("unexpected error: %s: %s", d.Summary(), d.Detail())
+			}
+		}
+		if expected != "" && !found {
+			t.Errorf("expected %q, got %v", expected, diags)
+		}
+	}
+}
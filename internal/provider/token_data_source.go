@@ -2,20 +2,27 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/thulasirajkomminar/influxdb3-management-go"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ datasource.DataSource              = &TokenDataSource{}
-	_ datasource.DataSourceWithConfigure = &TokenDataSource{}
+	_ datasource.DataSource                     = &TokenDataSource{}
+	_ datasource.DataSourceWithConfigure        = &TokenDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &TokenDataSource{}
 )
 
 // NewTokenDataSource is a helper function to simplify the provider implementation.
@@ -28,6 +35,7 @@ type TokenDataSource struct {
 	accountID influxdb3.UuidV4
 	client    influxdb3.ClientWithResponses
 	clusterID influxdb3.UuidV4
+	namespace databaseNamespace
 }
 
 // Metadata returns the data source type name.
@@ -61,15 +69,32 @@ func (d *TokenDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 			},
 			"description": schema.StringAttribute{
 				Computed:    true,
-				Description: "The description of the database token.",
+				Optional:    true,
+				Description: "The description of the database token. Exactly one of `id`, `description` or `description_regex` must be set.",
+			},
+			"description_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "A regular expression the description of the database token must match. Exactly one of `id`, `description` or `description_regex` must be set.",
+				Validators: []validator.String{
+					regexpValidator{},
+				},
 			},
 			"expires_at": schema.StringAttribute{
 				Computed:    true,
 				Description: "The date and time that the database token expires, if applicable. Uses RFC3339 format.",
 			},
+			"expires_in_seconds": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The number of seconds until the database token expires, if applicable. `0` once the token has expired.",
+			},
 			"id": schema.StringAttribute{
-				Required:    true,
-				Description: "The ID of the database token.",
+				Computed:    true,
+				Optional:    true,
+				Description: "The ID of the database token. Exactly one of `id`, `description` or `description_regex` must be set.",
+			},
+			"is_expired": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the database token has expired.",
 			},
 			"permissions": schema.ListNestedAttribute{
 				Computed:    true,
@@ -91,6 +116,17 @@ func (d *TokenDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 	}
 }
 
+// ConfigValidators returns the validators for the data source configuration.
+func (d *TokenDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("description"),
+			path.MatchRoot("description_regex"),
+		),
+	}
+}
+
 // Configure adds the provider configured client to the data source.
 func (d *TokenDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
@@ -110,17 +146,31 @@ func (d *TokenDataSource) Configure(ctx context.Context, req datasource.Configur
 	d.accountID = pd.accountID
 	d.client = pd.client
 	d.clusterID = pd.clusterID
+	d.namespace = pd.namespace
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (d *TokenDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
-	var state TokenModel
+	var state TokenDataSourceModel
 
 	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// Look up the token ID by description
+	if state.Id.IsNull() {
+		tokenId, err := d.findTokenId(ctx, state)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error getting token",
+				err.Error(),
+			)
+			return
+		}
+		state.Id = types.StringValue(tokenId)
+	}
+
 	// parse the token ID
 	tokenId, err := uuid.Parse(state.Id.ValueString())
 	if err != nil {
@@ -163,11 +213,17 @@ func (d *TokenDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	state.ClusterId = types.StringValue(readToken.ClusterId.String())
 	state.Description = types.StringValue(readToken.Description)
 	state.Id = types.StringValue(readToken.Id.String())
-	state.Permissions = getPermissions(readToken.Permissions)
-
-	if readToken.ExpiresAt != nil {
-		state.ExpiresAt = types.StringValue(readToken.ExpiresAt.Format(time.RFC3339))
+	permissions, ok := d.namespace.unqualifyPermissions(getPermissions(readToken.Permissions))
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Token not found",
+			fmt.Sprintf("Token with ID %s has permissions on databases outside the database_name_prefix %q of the provider", state.Id.ValueString(), d.namespace),
+		)
+		return
 	}
+	state.Permissions = permissions
+
+	state.setExpiry(readToken.ExpiresAt)
 
 	// Set state
 	diags := resp.State.Set(ctx, &state)
@@ -176,3 +232,50 @@ func (d *TokenDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 }
+
+// findTokenId returns the ID of the only database token whose description
+// matches the description or description_regex of the data source.
+func (d *TokenDataSource) findTokenId(ctx context.Context, state TokenDataSourceModel) (string, error) {
+	match := func(description string) bool {
+		return description == state.Description.ValueString()
+	}
+	if !state.DescriptionRegex.IsNull() {
+		descriptionRegex, err := regexp.Compile(state.DescriptionRegex.ValueString())
+		if err != nil {
+			return "", err
+		}
+		match = descriptionRegex.MatchString
+	}
+
+	readTokensResponse, err := d.client.GetDatabaseTokensWithResponse(ctx, d.accountID, d.clusterID)
+	if err != nil {
+		return "", err
+	}
+
+	if readTokensResponse.StatusCode() != 200 {
+		errMsg, err := formatErrorResponse(readTokensResponse, readTokensResponse.StatusCode())
+		if err != nil {
+			return "", err
+		}
+		return "", errors.New(errMsg)
+	}
+
+	var tokenIds []string
+	for _, token := range *readTokensResponse.JSON200 {
+		if _, ok := d.namespace.unqualifyPermissions(getPermissions(token.Permissions)); !ok {
+			continue
+		}
+		if match(token.Description) {
+			tokenIds = append(tokenIds, token.Id.String())
+		}
+	}
+
+	switch len(tokenIds) {
+	case 0:
+		return "", errors.New("no database token matches the given description")
+	case 1:
+		return tokenIds[0], nil
+	default:
+		return "", fmt.Errorf("%d database tokens match the given description, the lookup must match exactly one: %s", len(tokenIds), strings.Join(tokenIds, ", "))
+	}
+}
@@ -3,9 +3,12 @@ package provider
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/thulasirajkomminar/influxdb3-management-go"
@@ -13,14 +16,40 @@ import (
 
 // TokenModel maps InfluxDB database token schema data.
 type TokenModel struct {
-	AccessToken types.String           `tfsdk:"access_token"`
-	AccountId   types.String           `tfsdk:"account_id"`
-	CreatedAt   types.String           `tfsdk:"created_at"`
-	ClusterId   types.String           `tfsdk:"cluster_id"`
-	Description types.String           `tfsdk:"description"`
-	ExpiresAt   types.String           `tfsdk:"expires_at"`
-	Id          types.String           `tfsdk:"id"`
-	Permissions []TokenPermissionModel `tfsdk:"permissions"`
+	AccessToken      types.String           `tfsdk:"access_token"`
+	AccountId        types.String           `tfsdk:"account_id"`
+	CreatedAt        types.String           `tfsdk:"created_at"`
+	ClusterId        types.String           `tfsdk:"cluster_id"`
+	Description      types.String           `tfsdk:"description"`
+	ExpiresAt        types.String           `tfsdk:"expires_at"`
+	ExpiresInSeconds types.Int64            `tfsdk:"expires_in_seconds"`
+	Id               types.String           `tfsdk:"id"`
+	IsExpired        types.Bool             `tfsdk:"is_expired"`
+	Permissions      []TokenPermissionModel `tfsdk:"permissions"`
+}
+
+// TokenDataSourceModel maps InfluxDB database token data source schema data.
+type TokenDataSourceModel struct {
+	TokenModel
+	DescriptionRegex types.String `tfsdk:"description_regex"`
+}
+
+// TokenResourceModel maps InfluxDB database token resource schema data.
+type TokenResourceModel struct {
+	AccessToken      types.String `tfsdk:"access_token"`
+	AccountId        types.String `tfsdk:"account_id"`
+	CreatedAt        types.String `tfsdk:"created_at"`
+	ClusterId        types.String `tfsdk:"cluster_id"`
+	Description      types.String `tfsdk:"description"`
+	ExpiresAt        types.String `tfsdk:"expires_at"`
+	ExpiresIn        types.String `tfsdk:"expires_in"`
+	ExpiresInSeconds types.Int64  `tfsdk:"expires_in_seconds"`
+	Id               types.String `tfsdk:"id"`
+	IsExpired        types.Bool   `tfsdk:"is_expired"`
+	Permissions      types.Set    `tfsdk:"permissions"`
+	ReadDatabases    types.Set    `tfsdk:"read_databases"`
+	RotateBefore     types.String `tfsdk:"rotate_before"`
+	WriteDatabases   types.Set    `tfsdk:"write_databases"`
 }
 
 // TokenPermissionModel maps InfluxDB database token permission schema data.
@@ -29,6 +58,14 @@ type TokenPermissionModel struct {
 	Resource types.String `tfsdk:"resource"`
 }
 
+// GetAttrType returns the attribute type for the TokenPermissionModel.
+func (m TokenPermissionModel) GetAttrType() attr.Type {
+	return types.ObjectType{AttrTypes: map[string]attr.Type{
+		"action":   types.StringType,
+		"resource": types.StringType,
+	}}
+}
+
 type rfc3339Validator struct{}
 
 func (v rfc3339Validator) Description(ctx context.Context) string {
@@ -64,6 +101,68 @@ func (v rfc3339Validator) ValidateString(ctx context.Context, req validator.Stri
 	}
 }
 
+type durationValidator struct{}
+
+func (v durationValidator) Description(ctx context.Context) string {
+	return "value must be a valid positive duration"
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return "value must be a valid positive duration"
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration",
+			fmt.Sprintf("The value must be a valid duration (e.g., 720h or 90m). Error: %s", err.Error()),
+		)
+		return
+	}
+
+	if d <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Duration Must Be Positive",
+			fmt.Sprintf("The value must be a duration greater than zero, but got: %s", value),
+		)
+	}
+}
+
+// setExpiry sets the expiry of the token along with whether it has expired
+// and the number of seconds until it does.
+func (m *TokenModel) setExpiry(expiresAt *time.Time) {
+	m.ExpiresAt = types.StringNull()
+	if expiresAt != nil {
+		m.ExpiresAt = types.StringValue(expiresAt.Format(time.RFC3339))
+	}
+	m.ExpiresInSeconds, m.IsExpired = expiryStatus(expiresAt)
+}
+
+// setExpiryStatus sets whether the token has expired and the number of
+// seconds until it does.
+func (m *TokenResourceModel) setExpiryStatus(expiresAt *time.Time) {
+	m.ExpiresInSeconds, m.IsExpired = expiryStatus(expiresAt)
+}
+
+// expiryStatus returns the number of seconds until a token expires, and
+// whether it has expired.
+func expiryStatus(expiresAt *time.Time) (types.Int64, types.Bool) {
+	if expiresAt == nil {
+		return types.Int64Null(), types.BoolValue(false)
+	}
+
+	remaining := int64(time.Until(*expiresAt).Seconds())
+	return types.Int64Value(max(remaining, 0)), types.BoolValue(remaining <= 0)
+}
+
 func getPermissions(permissions []influxdb3.DatabaseTokenPermission) []TokenPermissionModel {
 	permissionsState := []TokenPermissionModel{}
 	for _, permission := range permissions {
@@ -74,5 +173,113 @@ func getPermissions(permissions []influxdb3.DatabaseTokenPermission) []TokenPerm
 		}
 		permissionsState = append(permissionsState, permissionState)
 	}
+
+	// Order permissions so the result does not depend on the order the API
+	// returns them in.
+	sortPermissions(permissionsState)
 	return permissionsState
 }
+
+// sortPermissions orders permissions by resource and then by action.
+func sortPermissions(permissions []TokenPermissionModel) {
+	sort.Slice(permissions, func(i, j int) bool {
+		if permissions[i].Resource.ValueString() != permissions[j].Resource.ValueString() {
+			return permissions[i].Resource.ValueString() < permissions[j].Resource.ValueString()
+		}
+		return permissions[i].Action.ValueString() < permissions[j].Action.ValueString()
+	})
+}
+
+// setPermissions sets the permissions of the token along with the
+// read_databases and write_databases shorthand derived from them.
+func (m *TokenResourceModel) setPermissions(permissions []TokenPermissionModel) {
+	m.Permissions = permissionsValue(permissions)
+	m.ReadDatabases, m.WriteDatabases = getDatabasePermissions(permissions)
+}
+
+// permissionsValue returns the permissions as a set value, which is null for
+// nil permissions.
+func permissionsValue(permissions []TokenPermissionModel) types.Set {
+	elementType := TokenPermissionModel{}.GetAttrType().(types.ObjectType)
+	if permissions == nil {
+		return types.SetNull(elementType)
+	}
+
+	elements := make([]attr.Value, 0, len(permissions))
+	for _, permission := range permissions {
+		elements = append(elements, types.ObjectValueMust(elementType.AttrTypes, map[string]attr.Value{
+			"action":   permission.Action,
+			"resource": permission.Resource,
+		}))
+	}
+	return types.SetValueMust(elementType, elements)
+}
+
+// tokenPermissions returns the permissions of a permissions set, ordered by
+// resource and then by action, and whether the set is fully known. Unknown
+// permissions are left out, so callers that need the complete set must fail
+// when it is not fully known.
+func tokenPermissions(permissions types.Set) ([]TokenPermissionModel, bool) {
+	models := []TokenPermissionModel{}
+	known := !permissions.IsUnknown()
+	for _, element := range permissions.Elements() {
+		object, ok := element.(types.Object)
+		if !ok || object.IsNull() || object.IsUnknown() {
+			known = false
+			continue
+		}
+		action, _ := object.Attributes()["action"].(types.String)
+		resource, _ := object.Attributes()["resource"].(types.String)
+		if action.IsUnknown() || resource.IsUnknown() {
+			known = false
+		}
+		models = append(models, TokenPermissionModel{Action: action, Resource: resource})
+	}
+	sortPermissions(models)
+	return models, known
+}
+
+// getDatabasePermissions returns the databases the permissions grant read and
+// write access to.
+func getDatabasePermissions(permissions []TokenPermissionModel) (types.Set, types.Set) {
+	readDatabases := []attr.Value{}
+	writeDatabases := []attr.Value{}
+	for _, permission := range permissions {
+		switch permission.Action.ValueString() {
+		case "read":
+			readDatabases = append(readDatabases, permission.Resource)
+		case "write":
+			writeDatabases = append(writeDatabases, permission.Resource)
+		}
+	}
+	return types.SetValueMust(types.StringType, readDatabases), types.SetValueMust(types.StringType, writeDatabases)
+}
+
+// expandDatabasePermissions expands the read_databases and write_databases
+// shorthand into permissions.
+func expandDatabasePermissions(ctx context.Context, readDatabases types.Set, writeDatabases types.Set) ([]TokenPermissionModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	permissions := []TokenPermissionModel{}
+	for action, databases := range map[string]types.Set{"read": readDatabases, "write": writeDatabases} {
+		if databases.IsNull() || databases.IsUnknown() {
+			continue
+		}
+
+		var resources []types.String
+		diags.Append(databases.ElementsAs(ctx, &resources, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		for _, resource := range resources {
+			permissions = append(permissions, TokenPermissionModel{
+				Action:   types.StringValue(action),
+				Resource: resource,
+			})
+		}
+	}
+
+	sortPermissions(permissions)
+	return permissions, diags
+}
@@ -0,0 +1,293 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/thulasirajkomminar/influxdb3-management-go"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &TokenPermissionsDataSource{}
+	_ datasource.DataSourceWithConfigure = &TokenPermissionsDataSource{}
+)
+
+// NewTokenPermissionsDataSource is a helper function to simplify the provider implementation.
+func NewTokenPermissionsDataSource() datasource.DataSource {
+	return &TokenPermissionsDataSource{}
+}
+
+// TokenPermissionsDataSource is the data source implementation.
+type TokenPermissionsDataSource struct {
+	accountID     influxdb3.UuidV4
+	client        influxdb3.ClientWithResponses
+	clusterID     influxdb3.UuidV4
+	databaseCache *databaseCache
+	namespace     databaseNamespace
+}
+
+// TokenPermissionsDataSourceModel describes the data source data model.
+type TokenPermissionsDataSourceModel struct {
+	Permissions []TokenPermissionModel          `tfsdk:"permissions"`
+	Statements  []TokenPermissionStatementModel `tfsdk:"statements"`
+}
+
+// TokenPermissionStatementModel maps a statement of the token permissions data source.
+type TokenPermissionStatementModel struct {
+	Actions          []types.String `tfsdk:"actions"`
+	DatabasePatterns []types.String `tfsdk:"database_patterns"`
+	Databases        []types.String `tfsdk:"databases"`
+	Sid              types.String   `tfsdk:"sid"`
+}
+
+// Metadata returns the data source type name.
+func (d *TokenPermissionsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_token_permissions"
+}
+
+// Schema defines the schema for the data source.
+func (d *TokenPermissionsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Composes database token permissions from statements. The statements are merged and deduplicated into a canonical list of permissions that can be used as the `permissions` of an `influxdb3_token` resource.",
+
+		Attributes: map[string]schema.Attribute{
+			"permissions": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The canonical list of permissions granted by the statements, ordered by resource and then by action. Permissions on a database are left out when the same action is granted on all databases (`*`).",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"action": schema.StringAttribute{
+							Computed:    true,
+							Description: "The action the database token permission allows.",
+						},
+						"resource": schema.StringAttribute{
+							Computed:    true,
+							Description: "The resource the database token permission applies to. `*` refers to all databases.",
+						},
+					},
+				},
+			},
+			"statements": schema.ListNestedAttribute{
+				Required:    true,
+				Description: "The statements granting permissions. Database token permissions only allow actions, so statements granting the same action on the same database, under the same or different `sid`s, are merged rather than rejected. The only conflict rejected is statements sharing a `sid` that are not identical.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"actions": schema.SetAttribute{
+							Required:    true,
+							ElementType: types.StringType,
+							Description: "The actions the statement allows. Valid values are `read` or `write`.",
+							Validators: []validator.Set{
+								setvalidator.SizeAtLeast(1),
+								setvalidator.ValueStringsAre(stringvalidator.OneOf([]string{"read", "write"}...)),
+							},
+						},
+						"database_patterns": schema.SetAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Regular expressions matched against the names of the cluster databases, without the `database_name_prefix` of the provider. The statement applies to every cluster database whose name matches one of them.",
+							Validators: []validator.Set{
+								setvalidator.ValueStringsAre(regexpValidator{}),
+								setvalidator.AtLeastOneOf(path.MatchRelative().AtParent().AtName("databases")),
+							},
+						},
+						"databases": schema.SetAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "The names of the databases the statement applies to. `*` refers to all databases.",
+						},
+						"sid": schema.StringAttribute{
+							Optional:    true,
+							Description: "An optional identifier for the statement. Statements sharing a `sid` must be identical, so that a statement contributed twice, e.g. by two modules, cannot silently grant different permissions.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *TokenPermissionsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	pd, ok := req.ProviderData.(providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected influxdb3.ClientWithResponses, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.accountID = pd.accountID
+	d.client = pd.client
+	d.clusterID = pd.clusterID
+	d.databaseCache = pd.databaseCache
+	d.namespace = pd.namespace
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *TokenPermissionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state TokenPermissionsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only list the cluster databases when a statement needs them
+	var databases []string
+	if slices.ContainsFunc(state.Statements, func(statement TokenPermissionStatementModel) bool {
+		return len(statement.DatabasePatterns) > 0
+	}) {
+		readDatabasesResponse, err := d.databaseCache.list(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error getting databases",
+				err.Error(),
+			)
+			return
+		}
+
+		if readDatabasesResponse.StatusCode() != 200 {
+			errMsg, err := formatErrorResponse(readDatabasesResponse, readDatabasesResponse.StatusCode())
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error formatting error response",
+					err.Error(),
+				)
+				return
+			}
+			resp.Diagnostics.AddError(
+				"Error getting databases",
+				errMsg,
+			)
+			return
+		}
+
+		for _, database := range *readDatabasesResponse.JSON200 {
+			if name, ok := d.namespace.unqualify(database.Name); ok {
+				databases = append(databases, name)
+			}
+		}
+	}
+
+	permissions, diags := mergePermissionStatements(state.Statements, databases)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Permissions = permissions
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// mergePermissionStatements merges the statements into a canonical list of
+// permissions, matching database patterns against the given databases.
+// Permissions only allow actions, so overlapping grants are merged; the only
+// conflict is statements that share a sid but are not identical.
+func mergePermissionStatements(statements []TokenPermissionStatementModel, databases []string) ([]TokenPermissionModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	statementsBySid := map[string]string{}
+	granted := map[string]map[string]bool{}
+	for i, statement := range statements {
+		if !statement.Sid.IsNull() {
+			sid := statement.Sid.ValueString()
+			key := statement.canonical()
+			if existing, ok := statementsBySid[sid]; ok && existing != key {
+				diags.AddAttributeError(
+					path.Root("statements").AtListIndex(i).AtName("sid"),
+					"Conflicting Token Permission Statements",
+					fmt.Sprintf("Statements with sid %q grant different permissions. Statements sharing a sid must be identical.", sid),
+				)
+				continue
+			}
+			statementsBySid[sid] = key
+		}
+
+		resources := map[string]bool{}
+		for _, database := range statement.Databases {
+			resources[database.ValueString()] = true
+		}
+		for _, pattern := range statement.DatabasePatterns {
+			databaseRegex, err := regexp.Compile(pattern.ValueString())
+			if err != nil {
+				diags.AddAttributeError(
+					path.Root("statements").AtListIndex(i).AtName("database_patterns"),
+					"Invalid Regular Expression",
+					err.Error(),
+				)
+				continue
+			}
+			for _, database := range databases {
+				if databaseRegex.MatchString(database) {
+					resources[database] = true
+				}
+			}
+		}
+
+		for _, action := range statement.Actions {
+			if granted[action.ValueString()] == nil {
+				granted[action.ValueString()] = map[string]bool{}
+			}
+			for resource := range resources {
+				granted[action.ValueString()][resource] = true
+			}
+		}
+	}
+
+	permissions := []TokenPermissionModel{}
+	for action, resources := range granted {
+		for resource := range resources {
+			// A permission on all databases covers the database permissions
+			// for the same action.
+			if resources["*"] && resource != "*" {
+				continue
+			}
+			permissions = append(permissions, TokenPermissionModel{
+				Action:   types.StringValue(action),
+				Resource: types.StringValue(resource),
+			})
+		}
+	}
+	sortPermissions(permissions)
+
+	return permissions, diags
+}
+
+// canonical returns a representation of the statement that does not depend on
+// the order of its values, for comparing statements.
+func (m TokenPermissionStatementModel) canonical() string {
+	canonical := func(values []types.String) string {
+		strs := make([]string, 0, len(values))
+		for _, value := range values {
+			strs = append(strs, value.ValueString())
+		}
+		sort.Strings(strs)
+		return strings.Join(slices.Compact(strs), ",")
+	}
+	return canonical(m.Actions) + ";" + canonical(m.Databases) + ";" + canonical(m.DatabasePatterns)
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccTokenPermissionsDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + testAccTokenPermissionsDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.influxdb3_token_permissions.test", "permissions.#", "3"),
+					resource.TestCheckResourceAttr("data.influxdb3_token_permissions.test", "permissions.0.action", "read"),
+					resource.TestCheckResourceAttr("data.influxdb3_token_permissions.test", "permissions.0.resource", "signals"),
+				),
+			},
+		},
+	})
+}
+
+const testAccTokenPermissionsDataSourceConfig = `
+data "influxdb3_token_permissions" "test" {
+  statements = [
+    {
+      actions   = ["read", "write"]
+      databases = ["signals"]
+    },
+    {
+      sid       = "telemetry"
+      actions   = ["read"]
+      databases = ["telemetry", "signals"]
+    },
+  ]
+}
+`
+
+func TestMergePermissionStatements(t *testing.T) {
+	strs := func(values ...string) []types.String {
+		result := make([]types.String, 0, len(values))
+		for _, value := range values {
+			result = append(result, types.StringValue(value))
+		}
+		return result
+	}
+
+	testCases := map[string]struct {
+		statements    []TokenPermissionStatementModel
+		databases     []string
+		expected      [][2]string
+		expectedError bool
+	}{
+		"deduplicate": {
+			statements: []TokenPermissionStatementModel{
+				{Actions: strs("write", "read"), Databases: strs("signals")},
+				{Actions: strs("read"), Databases: strs("signals", "telemetry")},
+			},
+			expected: [][2]string{{"read", "signals"}, {"write", "signals"}, {"read", "telemetry"}},
+		},
+		"patterns": {
+			statements: []TokenPermissionStatementModel{
+				{Actions: strs("read"), DatabasePatterns: strs("^signals_")},
+			},
+			databases: []string{"signals_eu", "signals_us", "telemetry"},
+			expected:  [][2]string{{"read", "signals_eu"}, {"read", "signals_us"}},
+		},
+		"wildcard": {
+			statements: []TokenPermissionStatementModel{
+				{Actions: strs("read"), Databases: strs("*")},
+				{Actions: strs("read", "write"), Databases: strs("signals")},
+			},
+			expected: [][2]string{{"read", "*"}, {"write", "signals"}},
+		},
+		"same-sid": {
+			statements: []TokenPermissionStatementModel{
+				{Sid: types.StringValue("signals"), Actions: strs("read", "write"), Databases: strs("signals")},
+				{Sid: types.StringValue("signals"), Actions: strs("write", "read"), Databases: strs("signals")},
+			},
+			expected: [][2]string{{"read", "signals"}, {"write", "signals"}},
+		},
+		"overlapping-sids": {
+			statements: []TokenPermissionStatementModel{
+				{Sid: types.StringValue("ingest"), Actions: strs("write"), Databases: strs("signals")},
+				{Sid: types.StringValue("alerts"), Actions: strs("read", "write"), Databases: strs("signals")},
+			},
+			expected: [][2]string{{"read", "signals"}, {"write", "signals"}},
+		},
+		"conflicting-sid": {
+			statements: []TokenPermissionStatementModel{
+				{Sid: types.StringValue("signals"), Actions: strs("read"), Databases: strs("signals")},
+				{Sid: types.StringValue("signals"), Actions: strs("write"), Databases: strs("signals")},
+			},
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := mergePermissionStatements(testCase.statements, testCase.databases)
+			if diags.HasError() != testCase.expectedError {
+				t.Fatalf("expected error %t, got diagnostics: %v", testCase.expectedError, diags)
+			}
+			if testCase.expectedError {
+				return
+			}
+
+			if len(got) != len(testCase.expected) {
+				t.Fatalf("expected %d permissions, got %d: %v", len(testCase.expected), len(got), got)
+			}
+			for i, permission := range got {
+				if permission.Action.ValueString() != testCase.expected[i][0] || permission.Resource.ValueString() != testCase.expected[i][1] {
+					t.Errorf("expected permission %d to be %v, got %s %s", i, testCase.expected[i], permission.Action.ValueString(), permission.Resource.ValueString())
+				}
+			}
+		})
+	}
+}
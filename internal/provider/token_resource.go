@@ -3,26 +3,34 @@ package provider
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/thulasirajkomminar/influxdb3-management-go"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var (
-	_ resource.Resource                = &TokenResource{}
-	_ resource.ResourceWithImportState = &TokenResource{}
-	_ resource.ResourceWithImportState = &TokenResource{}
+	_ resource.Resource                 = &TokenResource{}
+	_ resource.ResourceWithImportState  = &TokenResource{}
+	_ resource.ResourceWithImportState  = &TokenResource{}
+	_ resource.ResourceWithModifyPlan   = &TokenResource{}
+	_ resource.ResourceWithMoveState    = &TokenResource{}
+	_ resource.ResourceWithUpgradeState = &TokenResource{}
 )
 
 // NewTokenResource is a helper function to simplify the provider implementation.
@@ -32,9 +40,16 @@ func NewTokenResource() resource.Resource {
 
 // TokenResource defines the resource implementation.
 type TokenResource struct {
-	accountID influxdb3.UuidV4
-	client    influxdb3.ClientWithResponses
-	clusterID influxdb3.UuidV4
+	accountID           influxdb3.UuidV4
+	auditLog            *auditLog
+	client              influxdb3.ClientWithResponses
+	clusterID           influxdb3.UuidV4
+	databaseCache       *databaseCache
+	namespace           databaseNamespace
+	expiryWarningWindow time.Duration
+	policy              *providerPolicy
+	readOnly            bool
+	strictPermissions   bool
 }
 
 // Metadata returns the resource type name.
@@ -45,6 +60,8 @@ func (r *TokenResource) Metadata(ctx context.Context, req resource.MetadataReque
 // Schema defines the schema for the resource.
 func (r *TokenResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: TOKEN_RESOURCE_SCHEMA_VERSION,
+
 		// This description is used by the documentation generator and the language server.
 		Description: "Creates and manages a token and returns the generated database token. Use this resource to create/manage a token, which generates an database token with permissions to read or write to a specific database.",
 
@@ -74,15 +91,25 @@ func (r *TokenResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Description: "The description of the database token.",
 			},
 			"expires_at": schema.StringAttribute{
+				Computed:    true,
 				Optional:    true,
-				Description: "The date and time that the database token expires, if applicable. Uses RFC3339 format(for example: 2020-01-01T00:00:00Z).",
+				Description: "The date and time that the database token expires, if applicable. Uses RFC3339 format(for example: 2020-01-01T00:00:00Z). Conflicts with `expires_in`. **Note:** The expiry of a token can't be updated. An update will result in resource replacement.",
 				Validators: []validator.String{
 					rfc3339Validator{},
+					stringvalidator.ConflictsWith(path.MatchRoot("expires_in")),
 				},
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+			},
+			"expires_in": schema.StringAttribute{
+				Optional:    true,
+				Description: "The lifetime of the database token as a duration (for example: `720h`). The expiry is computed into `expires_at` when the token is created. Conflicts with `expires_at`. **Note:** An update will result in resource replacement.",
+				Validators: []validator.String{
+					durationValidator{},
 				},
 			},
+			"expires_in_seconds": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The number of seconds until the database token expires, as of the last refresh, if applicable. `0` once the token has expired.",
+			},
 			"id": schema.StringAttribute{
 				Computed:    true,
 				Description: "The ID of the database token.",
@@ -90,11 +117,16 @@ func (r *TokenResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"permissions": schema.ListNestedAttribute{
-				Required:    true,
-				Description: "The list of permissions the database token allows.",
-				Validators: []validator.List{
-					listvalidator.UniqueValues(),
+			"is_expired": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the database token had expired as of the last refresh.",
+			},
+			"permissions": schema.SetNestedAttribute{
+				Computed:    true,
+				Optional:    true,
+				Description: "The set of permissions the database token allows. Conflicts with `read_databases` and `write_databases`.",
+				Validators: []validator.Set{
+					setvalidator.AtLeastOneOf(path.MatchRoot("read_databases"), path.MatchRoot("write_databases")),
 				},
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
@@ -112,13 +144,382 @@ func (r *TokenResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					},
 				},
 			},
+			"rotate_before": schema.StringAttribute{
+				Optional:    true,
+				Description: "The duration before `expires_at` within which the database token is replaced with a new one (for example: `168h`). Requires `expires_in`. Use together with the `create_before_destroy` lifecycle argument to rotate the token without downtime.",
+				Validators: []validator.String{
+					durationValidator{},
+					stringvalidator.AlsoRequires(path.MatchRoot("expires_in")),
+				},
+			},
+			"read_databases": schema.SetAttribute{
+				Computed:    true,
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The databases the database token can read from. A shorthand for `permissions` with the `read` action. Conflicts with `permissions`.",
+				Validators: []validator.Set{
+					setvalidator.ConflictsWith(path.MatchRoot("permissions")),
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"write_databases": schema.SetAttribute{
+				Computed:    true,
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The databases the database token can write to. A shorthand for `permissions` with the `write` action. Conflicts with `permissions`.",
+				Validators: []validator.Set{
+					setvalidator.ConflictsWith(path.MatchRoot("permissions")),
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
 		},
 	}
 }
 
+// ModifyPlan completes the plan with the permissions and expiry the token will
+// be created or updated with, and checks the plan against the provider policy
+// and the databases of the cluster.
+func (r *TokenResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when the resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	r.modifyPlanPermissions(ctx, req, resp)
+	r.modifyPlanExpiry(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.validatePolicy(ctx, resp.Plan, req.State)...)
+	r.validatePermissionDatabases(ctx, req, resp)
+}
+
+// validatePolicy checks the planned database token against the provider
+// policy. The lifetime of a database token that keeps its expiry is measured
+// from its creation, and that of a new database token from now.
+func (r *TokenResource) validatePolicy(ctx context.Context, plan tfsdk.Plan, state tfsdk.State) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if r.policy == nil {
+		return diags
+	}
+
+	var token TokenResourceModel
+	var permissions types.Set
+	diags.Append(plan.GetAttribute(ctx, path.Root("description"), &token.Description)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("expires_at"), &token.ExpiresAt)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("expires_in"), &token.ExpiresIn)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("permissions"), &permissions)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("read_databases"), &token.ReadDatabases)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("write_databases"), &token.WriteDatabases)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if !state.Raw.IsNull() {
+		var stateToken TokenResourceModel
+		diags.Append(state.GetAttribute(ctx, path.Root("created_at"), &stateToken.CreatedAt)...)
+		diags.Append(state.GetAttribute(ctx, path.Root("expires_at"), &stateToken.ExpiresAt)...)
+		if diags.HasError() {
+			return diags
+		}
+		if token.ExpiresAt.Equal(stateToken.ExpiresAt) {
+			token.CreatedAt = stateToken.CreatedAt
+		}
+	}
+
+	switch {
+	case permissions.IsNull():
+		if isFullyKnown(ctx, token.ReadDatabases) && isFullyKnown(ctx, token.WriteDatabases) {
+			expanded, expandDiags := expandDatabasePermissions(ctx, token.ReadDatabases, token.WriteDatabases)
+			diags.Append(expandDiags...)
+			token.Permissions = permissionsValue(expanded)
+		}
+	default:
+		// Known permissions are checked, the others once they are known
+		token.Permissions = permissions
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	diags.Append(r.policy.validateToken(token)...)
+	return diags
+}
+
+// modifyPlanPermissions keeps permissions and the read_databases and
+// write_databases shorthand consistent with each other in the plan.
+func (r *TokenResource) modifyPlanPermissions(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var configPermissions types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("permissions"), &configPermissions)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan TokenResourceModel
+	if configPermissions.IsNull() {
+		var readDatabases, writeDatabases types.Set
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("read_databases"), &readDatabases)...)
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("write_databases"), &writeDatabases)...)
+		if resp.Diagnostics.HasError() || !isFullyKnown(ctx, readDatabases) || !isFullyKnown(ctx, writeDatabases) {
+			return
+		}
+
+		permissions, diags := expandDatabasePermissions(ctx, readDatabases, writeDatabases)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		plan.setPermissions(permissions)
+	} else {
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("permissions"), &plan.Permissions)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// The shorthand is only known once every permission is
+		permissions, known := tokenPermissions(plan.Permissions)
+		if !known {
+			return
+		}
+		plan.setPermissions(permissions)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("permissions"), plan.Permissions)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("read_databases"), plan.ReadDatabases)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("write_databases"), plan.WriteDatabases)...)
+}
+
+// validatePermissionDatabases warns about planned permissions that name a
+// database which does not exist in the cluster, or fails the plan when
+// strict_permissions is set. Database names only known during apply are left
+// to Create and Update.
+func (r *TokenResource) validatePermissionDatabases(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var planPermissions types.Set
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("permissions"), &planPermissions)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The provider is not configured yet, e.g. during validation
+	if r.databaseCache == nil {
+		return
+	}
+
+	permissions, _ := tokenPermissions(planPermissions)
+	permissions = slices.DeleteFunc(permissions, func(permission TokenPermissionModel) bool {
+		return permission.Action.IsUnknown() || permission.Resource.IsUnknown()
+	})
+	if r.namespace != "" {
+		for _, permission := range permissions {
+			if permission.Resource.ValueString() == "*" {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("permissions"),
+					"Permission on all databases outside the namespace",
+					fmt.Sprintf("The %s permission applies to all databases (\"*\"), which would reach beyond the database_name_prefix %q of the provider. Name the databases instead.", permission.Action.ValueString(), r.namespace),
+				)
+				return
+			}
+		}
+	}
+
+	missing, ok := r.missingPermissionDatabases(ctx, permissions)
+	if !ok {
+		resp.Diagnostics.AddWarning(
+			"Unable to validate permissions",
+			"The databases of the cluster could not be listed, so the databases named by the permissions of the database token were not validated.",
+		)
+		return
+	}
+
+	for _, permission := range missing {
+		if r.strictPermissions {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("permissions"),
+				"Permission names an unknown database",
+				fmt.Sprintf("The %s permission names the database %q, which does not exist in the cluster, and strict_permissions is set. "+
+					"Create the database before planning the database token, or unset strict_permissions.", permission.Action.ValueString(), permission.Resource.ValueString()),
+			)
+			continue
+		}
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("permissions"),
+			"Permission names an unknown database",
+			fmt.Sprintf("The %s permission names the database %q, which does not exist in the cluster. The permission grants nothing until the database is created. "+
+				"If the database is created in the same configuration, refer to the name of its resource, for example influxdb3_database.example.name, so that it is created before the database token.", permission.Action.ValueString(), permission.Resource.ValueString()),
+		)
+	}
+}
+
+// checkPermissionDatabases fails, when strict_permissions is set, if a
+// permission names a database that does not exist in the cluster.
+func (r *TokenResource) checkPermissionDatabases(ctx context.Context, permissions []TokenPermissionModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !r.strictPermissions {
+		return diags
+	}
+
+	missing, ok := r.missingPermissionDatabases(ctx, permissions)
+	if !ok {
+		diags.AddWarning(
+			"Unable to validate permissions",
+			"The databases of the cluster could not be listed, so the databases named by the permissions of the database token were not validated.",
+		)
+		return diags
+	}
+
+	for _, permission := range missing {
+		diags.AddAttributeError(
+			path.Root("permissions"),
+			"Permission names an unknown database",
+			fmt.Sprintf("The %s permission names the database %q, which does not exist in the cluster, and strict_permissions is set. "+
+				"If the database is created in the same configuration, refer to the name of its resource, for example influxdb3_database.example.name, so that it is created before the database token.", permission.Action.ValueString(), permission.Resource.ValueString()),
+		)
+	}
+	return diags
+}
+
+// missingPermissionDatabases returns the permissions that name a database
+// which does not exist in the cluster, and false if the databases of the
+// cluster could not be listed.
+func (r *TokenResource) missingPermissionDatabases(ctx context.Context, permissions []TokenPermissionModel) ([]TokenPermissionModel, bool) {
+	readDatabasesResponse, err := r.databaseCache.list(ctx)
+	if err != nil || readDatabasesResponse.StatusCode() != 200 {
+		return nil, false
+	}
+
+	databases := make(map[string]bool)
+	for _, database := range *readDatabasesResponse.JSON200 {
+		databases[database.Name] = true
+	}
+
+	var missing []TokenPermissionModel
+	for _, permission := range permissions {
+		database := permission.Resource.ValueString()
+		if database == "*" || databases[r.namespace.qualify(database)] {
+			continue
+		}
+		missing = append(missing, permission)
+	}
+	return missing, true
+}
+
+// modifyPlanExpiry plans expires_at from expires_at or expires_in and replaces
+// the token when its expiry changes or when it is within rotate_before of
+// expiring.
+func (r *TokenResource) modifyPlanExpiry(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var expiresAt, expiresIn, rotateBefore types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("expires_at"), &expiresAt)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("expires_in"), &expiresIn)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("rotate_before"), &rotateBefore)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A token created from expires_in only knows its expiry once it exists
+	plannedExpiresAt := expiresAt
+	if expiresAt.IsNull() && !expiresIn.IsNull() {
+		plannedExpiresAt = types.StringUnknown()
+	}
+
+	requiresReplace := false
+	if !req.State.Raw.IsNull() {
+		var stateExpiresAt, stateExpiresIn types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("expires_at"), &stateExpiresAt)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("expires_in"), &stateExpiresIn)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		switch {
+		case !expiresAt.IsNull():
+			requiresReplace = !expiresAt.Equal(stateExpiresAt)
+		case !expiresIn.IsNull():
+			// Imported tokens have no expires_in, so keep their existing expiry
+			if expiresIn.Equal(stateExpiresIn) || (stateExpiresIn.IsNull() && !stateExpiresAt.IsNull()) {
+				plannedExpiresAt = stateExpiresAt
+			} else {
+				requiresReplace = true
+			}
+		default:
+			requiresReplace = !stateExpiresAt.IsNull()
+		}
+
+		if !requiresReplace && !rotateBefore.IsNull() && !rotateBefore.IsUnknown() && !plannedExpiresAt.IsNull() && !plannedExpiresAt.IsUnknown() {
+			expiry, err := time.Parse(time.RFC3339, plannedExpiresAt.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error parsing expires_at",
+					err.Error(),
+				)
+				return
+			}
+
+			window, err := time.ParseDuration(rotateBefore.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error parsing rotate_before",
+					err.Error(),
+				)
+				return
+			}
+
+			if time.Until(expiry) <= window {
+				resp.Diagnostics.AddAttributeWarning(
+					path.Root("rotate_before"),
+					"Database token will be rotated",
+					fmt.Sprintf("The database token expires at %s, which is within the rotate_before window of %s. A new token will be created to replace it.", plannedExpiresAt.ValueString(), rotateBefore.ValueString()),
+				)
+				plannedExpiresAt = types.StringUnknown()
+				requiresReplace = true
+			}
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("expires_at"), plannedExpiresAt)...)
+	if requiresReplace {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("expires_at"))
+	}
+
+	if plannedExpiresAt.IsNull() || plannedExpiresAt.IsUnknown() {
+		return
+	}
+
+	expiry, err := time.Parse(time.RFC3339, plannedExpiresAt.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error parsing expires_at",
+			err.Error(),
+		)
+		return
+	}
+
+	var description types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("description"), &description)...)
+
+	if remaining := time.Until(expiry); remaining <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("expires_at"),
+			"Database token has expired",
+			fmt.Sprintf("The database token %q expired at %s. Set a new expires_at or expires_in to replace it with a new token.", description.ValueString(), plannedExpiresAt.ValueString()),
+		)
+	} else if remaining <= r.expiryWarningWindow {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("expires_at"),
+			"Database token expires soon",
+			fmt.Sprintf("The database token %q expires at %s, in %s.", description.ValueString(), plannedExpiresAt.ValueString(), remaining.Truncate(time.Second)),
+		)
+	}
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *TokenResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan TokenModel
+	if r.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic("create", "database token"))
+		return
+	}
+
+	var plan TokenResourceModel
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -126,12 +527,31 @@ func (r *TokenResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	permissions, known := tokenPermissions(plan.Permissions)
+	if !known {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("permissions"),
+			"Unknown database token permissions",
+			"The permissions of the database token are not fully known. Please report this issue to the provider developers.",
+		)
+		return
+	}
+	resp.Diagnostics.Append(r.checkPermissionDatabases(ctx, permissions)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Generate API request body from plan
 	var permissionsRequest []influxdb3.DatabaseTokenPermission
-	for _, permission := range plan.Permissions {
+	var permissionDatabases []string
+	for _, permission := range permissions {
 		resource := influxdb3.DatabaseTokenPermissionResource{}
 
-		err := resource.FromClusterDatabaseName(permission.Resource.ValueString())
+		database := r.namespace.qualify(permission.Resource.ValueString())
+		if database != "*" {
+			permissionDatabases = append(permissionDatabases, database)
+		}
+		err := resource.FromClusterDatabaseName(database)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Validation error. Ensure the Resource is in the correct format.",
@@ -162,18 +582,72 @@ func (r *TokenResource) Create(ctx context.Context, req resource.CreateRequest,
 			return
 		}
 		createTokenRequest.ExpiresAt = &t
+	} else if !plan.ExpiresIn.IsNull() && !plan.ExpiresIn.IsUnknown() {
+		d, err := time.ParseDuration(plan.ExpiresIn.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error parsing expires_in",
+				err.Error(),
+			)
+			return
+		}
+		t := time.Now().UTC().Add(d).Truncate(time.Second)
+		createTokenRequest.ExpiresAt = &t
 	}
 
-	createTokenResponse, err := r.client.CreateDatabaseTokenWithResponse(ctx, r.accountID, r.clusterID, createTokenRequest)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating token",
-			"Could not create token, unexpected error: "+err.Error(),
-		)
-		return
-	}
+	var createTokenResponse *influxdb3.CreateDatabaseTokenResponse
+	var attempts *requestAttempts
+	databaseRetries := 0
+	for {
+		var createCtx context.Context
+		createCtx, attempts = withRequestAttempts(ctx)
+		var err error
+		createTokenResponse, err = r.client.CreateDatabaseTokenWithResponse(createCtx, r.accountID, r.clusterID, createTokenRequest)
+		resp.Diagnostics.Append(r.auditLog.record(auditEntry{
+			Operation: "create_token",
+			AccountID: r.accountID.String(),
+			ClusterID: r.clusterID.String(),
+			Object:    createTokenRequest.Description,
+			Request:   createTokenRequest,
+			Status:    auditStatus(createTokenResponse, err),
+			Error:     auditError(err),
+		})...)
+		if err == nil && createTokenResponse.StatusCode() == 200 {
+			break
+		}
+
+		// Databases created moments ago may not be visible to the cluster yet
+		if err == nil && databaseRetries < TOKEN_CREATE_DATABASE_RETRIES && databaseNotFound(createTokenResponse.JSON400, permissionDatabases) {
+			databaseRetries++
+			tflog.Debug(ctx, "Database of token permission not found, retrying", map[string]any{"retry": databaseRetries})
+			if !sleepContext(ctx, TOKEN_CREATE_DATABASE_RETRY_INTERVAL) {
+				resp.Diagnostics.AddError(
+					"Error creating token",
+					"Could not create token, unexpected error: "+ctx.Err().Error(),
+				)
+				return
+			}
+			continue
+		}
+
+		// An attempt of the request whose response was lost may have created
+		// the token. Its access token cannot be read back, so such a token is
+		// reported rather than adopted.
+		if ambiguousCreate(attempts, auditStatus(createTokenResponse, err), err) {
+			resp.Diagnostics.Append(r.checkAmbiguousCreate(ctx, createTokenRequest)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error creating token",
+				"Could not create token, unexpected error: "+err.Error(),
+			)
+			return
+		}
 
-	if createTokenResponse.StatusCode() != 200 {
 		errMsg, err := formatErrorResponse(createTokenResponse, createTokenResponse.StatusCode())
 		if err != nil {
 			resp.Diagnostics.AddError(
@@ -190,6 +664,11 @@ func (r *TokenResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 	createToken := *createTokenResponse.JSON200
 
+	// An earlier attempt of a retried request may have created another token
+	if attempts.count.Load() > 1 {
+		resp.Diagnostics.Append(r.checkRetriedCreate(ctx, createTokenRequest, createToken.Id.String())...)
+	}
+
 	// Map response body to schema and populate Computed attribute values
 	plan.AccessToken = types.StringValue(createToken.AccessToken)
 	plan.AccountId = types.StringValue(createToken.AccountId.String())
@@ -197,11 +676,22 @@ func (r *TokenResource) Create(ctx context.Context, req resource.CreateRequest,
 	plan.ClusterId = types.StringValue(createToken.ClusterId.String())
 	plan.Description = types.StringValue(createToken.Description)
 	plan.Id = types.StringValue(createToken.Id.String())
-	plan.Permissions = getPermissions(createToken.Permissions)
+	permissions, ok := r.namespace.unqualifyPermissions(getPermissions(createToken.Permissions))
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Error creating token",
+			fmt.Sprintf("Token with ID %s has permissions on databases outside the database_name_prefix %q of the provider", plan.Id.ValueString(), r.namespace),
+		)
+		return
+	}
+	plan.setPermissions(permissions)
 
 	if createToken.ExpiresAt != nil {
 		plan.ExpiresAt = types.StringValue(createToken.ExpiresAt.Format(time.RFC3339))
+	} else if plan.ExpiresAt.IsUnknown() {
+		plan.ExpiresAt = types.StringNull()
 	}
+	plan.setExpiryStatus(createToken.ExpiresAt)
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
@@ -210,10 +700,97 @@ func (r *TokenResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 }
 
+// checkAmbiguousCreate fails when a database token with the description,
+// permissions and expiry of the create request exists after an ambiguous
+// create, as it may have been created by the request. The access token of
+// such a token is lost and a token made by someone else cannot be told apart,
+// so it is neither adopted nor deleted.
+func (r *TokenResource) checkAmbiguousCreate(ctx context.Context, createTokenRequest influxdb3.CreateDatabaseTokenJSONRequestBody) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	matches, ok := r.matchingTokens(ctx, createTokenRequest, "")
+	if !ok {
+		diags.AddError(
+			"Unable to confirm token create",
+			fmt.Sprintf("The request creating database token %q failed in a way that may have created the token, and the database tokens could not be listed to check. "+
+				"Delete any database token with this description that is not in use before applying again.", createTokenRequest.Description),
+		)
+		return diags
+	}
+
+	if len(matches) > 0 {
+		diags.AddError(
+			"Unable to confirm token create",
+			fmt.Sprintf("The request creating database token %q failed, but database tokens %s have the same description, permissions and expiry, and may have been created by the request. "+
+				"Their access tokens cannot be read back. Delete the tokens that are not in use, or import the one to keep, before applying again.", createTokenRequest.Description, strings.Join(matches, ", ")),
+		)
+	}
+	return diags
+}
+
+// checkRetriedCreate warns about database tokens matching a create request
+// that succeeded after it was retried, as an earlier attempt whose response
+// was lost may have created one of them. For the same reasons as
+// checkAmbiguousCreate, they are reported rather than deleted.
+func (r *TokenResource) checkRetriedCreate(ctx context.Context, createTokenRequest influxdb3.CreateDatabaseTokenJSONRequestBody, createdID string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	matches, ok := r.matchingTokens(ctx, createTokenRequest, createdID)
+	if !ok {
+		diags.AddWarning(
+			"Unable to check for duplicate database tokens",
+			fmt.Sprintf("The request creating database token %q was retried before it succeeded, and the database tokens could not be listed to check whether an earlier attempt created another token. "+
+				"Delete any other database token with this description that is not in use.", createTokenRequest.Description),
+		)
+		return diags
+	}
+
+	if len(matches) > 0 {
+		diags.AddWarning(
+			"Possible duplicate database tokens",
+			fmt.Sprintf("The request creating database token %q was retried before it succeeded with token %s, and database tokens %s have the same description, permissions and expiry. "+
+				"An earlier attempt of the request may have created them, leaving tokens with live permissions that are not managed. Delete the tokens that are not in use.", createTokenRequest.Description, createdID, strings.Join(matches, ", ")),
+		)
+	}
+	return diags
+}
+
+// matchingTokens returns the IDs of the database tokens, other than the
+// excluded one, with the description, permissions and expiry of the create
+// request, and false if the database tokens could not be listed.
+func (r *TokenResource) matchingTokens(ctx context.Context, createTokenRequest influxdb3.CreateDatabaseTokenJSONRequestBody, excludedID string) ([]string, bool) {
+	readTokensResponse, err := r.client.GetDatabaseTokensWithResponse(ctx, r.accountID, r.clusterID)
+	if err != nil || readTokensResponse.StatusCode() != 200 {
+		return nil, false
+	}
+
+	requested := []TokenPermissionModel{}
+	if createTokenRequest.Permissions != nil {
+		requested = getPermissions(*createTokenRequest.Permissions)
+	}
+
+	var matches []string
+	for _, token := range *readTokensResponse.JSON200 {
+		if token.Id.String() == excludedID || token.Description != createTokenRequest.Description {
+			continue
+		}
+		if !slices.EqualFunc(getPermissions(token.Permissions), requested, func(a TokenPermissionModel, b TokenPermissionModel) bool {
+			return a.Action.Equal(b.Action) && a.Resource.Equal(b.Resource)
+		}) {
+			continue
+		}
+		if (token.ExpiresAt == nil) != (createTokenRequest.ExpiresAt == nil) || (token.ExpiresAt != nil && !token.ExpiresAt.Equal(*createTokenRequest.ExpiresAt)) {
+			continue
+		}
+		matches = append(matches, token.Id.String())
+	}
+	return matches, true
+}
+
 // Read refreshes the Terraform state with the latest data.
 func (r *TokenResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	// Get current state
-	var state TokenModel
+	var state TokenResourceModel
 
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
@@ -264,11 +841,20 @@ func (r *TokenResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	state.ClusterId = types.StringValue(readToken.ClusterId.String())
 	state.Description = types.StringValue(readToken.Description)
 	state.Id = types.StringValue(readToken.Id.String())
-	state.Permissions = getPermissions(readToken.Permissions)
+	permissions, ok := r.namespace.unqualifyPermissions(getPermissions(readToken.Permissions))
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Error getting token",
+			fmt.Sprintf("Token with ID %s has permissions on databases outside the database_name_prefix %q of the provider", state.Id.ValueString(), r.namespace),
+		)
+		return
+	}
+	state.setPermissions(permissions)
 
 	if readToken.ExpiresAt != nil {
 		state.ExpiresAt = types.StringValue(readToken.ExpiresAt.Format(time.RFC3339))
 	}
+	state.setExpiryStatus(readToken.ExpiresAt)
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
@@ -279,7 +865,12 @@ func (r *TokenResource) Read(ctx context.Context, req resource.ReadRequest, resp
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *TokenResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan TokenModel
+	if r.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic("update", "database token"))
+		return
+	}
+
+	var plan TokenResourceModel
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -297,12 +888,26 @@ func (r *TokenResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	permissions, known := tokenPermissions(plan.Permissions)
+	if !known {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("permissions"),
+			"Unknown database token permissions",
+			"The permissions of the database token are not fully known. Please report this issue to the provider developers.",
+		)
+		return
+	}
+	resp.Diagnostics.Append(r.checkPermissionDatabases(ctx, permissions)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Generate API request body from plan
 	var permissionsRequest []influxdb3.DatabaseTokenPermission
-	for _, permission := range plan.Permissions {
+	for _, permission := range permissions {
 		resource := influxdb3.DatabaseTokenPermissionResource{}
 
-		err := resource.FromClusterDatabaseName(permission.Resource.ValueString())
+		err := resource.FromClusterDatabaseName(r.namespace.qualify(permission.Resource.ValueString()))
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Validation error. Ensure the Resource is in the correct format.",
@@ -325,6 +930,15 @@ func (r *TokenResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	// Update existing token
 	updateTokenResponse, err := r.client.UpdateDatabaseTokenWithResponse(ctx, r.accountID, r.clusterID, tokenId, updateTokenRequest)
+	resp.Diagnostics.Append(r.auditLog.record(auditEntry{
+		Operation: "update_token",
+		AccountID: r.accountID.String(),
+		ClusterID: r.clusterID.String(),
+		Object:    tokenId.String(),
+		Request:   updateTokenRequest,
+		Status:    auditStatus(updateTokenResponse, err),
+		Error:     auditError(err),
+	})...)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating token",
@@ -356,11 +970,20 @@ func (r *TokenResource) Update(ctx context.Context, req resource.UpdateRequest,
 	plan.ClusterId = types.StringValue(updateToken.ClusterId.String())
 	plan.Description = types.StringValue(updateToken.Description)
 	plan.Id = types.StringValue(updateToken.Id.String())
-	plan.Permissions = getPermissions(updateToken.Permissions)
+	permissions, ok := r.namespace.unqualifyPermissions(getPermissions(updateToken.Permissions))
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Error updating token",
+			fmt.Sprintf("Token with ID %s has permissions on databases outside the database_name_prefix %q of the provider", plan.Id.ValueString(), r.namespace),
+		)
+		return
+	}
+	plan.setPermissions(permissions)
 
 	if updateToken.ExpiresAt != nil {
 		plan.ExpiresAt = types.StringValue(updateToken.ExpiresAt.Format(time.RFC3339))
 	}
+	plan.setExpiryStatus(updateToken.ExpiresAt)
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
@@ -371,7 +994,12 @@ func (r *TokenResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *TokenResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state TokenModel
+	if r.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic("delete", "database token"))
+		return
+	}
+
+	var state TokenResourceModel
 
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
@@ -391,6 +1019,14 @@ func (r *TokenResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 	// Delete existing token
 	deleteTokenResponse, err := r.client.DeleteDatabaseTokenWithResponse(ctx, r.accountID, r.clusterID, tokenId)
+	resp.Diagnostics.Append(r.auditLog.record(auditEntry{
+		Operation: "delete_token",
+		AccountID: r.accountID.String(),
+		ClusterID: r.clusterID.String(),
+		Object:    tokenId.String(),
+		Status:    auditStatus(deleteTokenResponse, err),
+		Error:     auditError(err),
+	})...)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting token",
@@ -433,10 +1069,64 @@ func (r *TokenResource) Configure(ctx context.Context, req resource.ConfigureReq
 	}
 
 	r.accountID = pd.accountID
+	r.auditLog = pd.auditLog
 	r.client = pd.client
 	r.clusterID = pd.clusterID
+	r.databaseCache = pd.databaseCache
+	r.namespace = pd.namespace
+	r.expiryWarningWindow = pd.expiryWarningWindow
+	r.policy = pd.policy
+	r.readOnly = pd.readOnly
+	r.strictPermissions = pd.strictPermissions
 }
 
 func (r *TokenResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
+
+// MoveState moves the state of a token managed under the provider's former
+// komminarlabs/influxdb3 namespace into this resource.
+func (r *TokenResource) MoveState(ctx context.Context) []resource.StateMover {
+	sourceSchema := tokenResourceSchemaV0()
+
+	return []resource.StateMover{
+		{
+			SourceSchema: &sourceSchema,
+			StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+				if !isLegacyProviderAddress(req.SourceProviderAddress) || req.SourceTypeName != "influxdb3_token" {
+					return
+				}
+
+				if req.SourceSchemaVersion != 0 || req.SourceState == nil {
+					resp.Diagnostics.AddError(
+						"Unable to move token state",
+						fmt.Sprintf("The %s resource state with schema version %d cannot be moved. Please report this issue to the provider developers.", req.SourceTypeName, req.SourceSchemaVersion),
+					)
+					return
+				}
+
+				var sourceState tokenModelV0
+
+				resp.Diagnostics.Append(req.SourceState.Get(ctx, &sourceState)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				state := sourceState.upgrade()
+				resp.Diagnostics.Append(resp.TargetState.Set(ctx, &state)...)
+			},
+		},
+	}
+}
+
+// UpgradeState upgrades the state of a token written by a prior schema version.
+func (r *TokenResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	schemaV0 := tokenResourceSchemaV0()
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   &schemaV0,
+			StateUpgrader: upgradeTokenStateV0,
+		},
+	}
+}
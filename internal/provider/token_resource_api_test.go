@@ -0,0 +1,241 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestTokenResourceReadOutsideNamespace(t *testing.T) {
+	testCases := map[string]struct {
+		database      string
+		expectedError string
+	}{
+		"inside namespace": {
+			database: "team-signals",
+		},
+		"outside namespace": {
+			database:      "signals",
+			expectedError: "Error getting token",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			id := api.addToken(map[string]any{
+				"description": "signals",
+				"permissions": []map[string]any{{"action": "read", "resource": testCase.database}},
+			})
+
+			r := &TokenResource{client: api.client, accountID: api.accountID, clusterID: api.clusterID, namespace: "team-"}
+			state := testResourceObject(t, r, map[string]tftypes.Value{"id": tftypes.NewValue(tftypes.String, id)})
+			resp := &resource.ReadResponse{State: state}
+			r.Read(t.Context(), resource.ReadRequest{State: state}, resp)
+
+			testCheckDiagnosticSummaries(t, resp.Diagnostics, testCase.expectedError, "")
+		})
+	}
+}
+
+func TestTokenResourceCreateAmbiguous(t *testing.T) {
+	permissionType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"action": tftypes.String, "resource": tftypes.String}}
+	permissions := func(action string) tftypes.Value {
+		return tftypes.NewValue(tftypes.Set{ElementType: permissionType}, []tftypes.Value{
+			tftypes.NewValue(permissionType, map[string]tftypes.Value{
+				"action":   tftypes.NewValue(tftypes.String, action),
+				"resource": tftypes.NewValue(tftypes.String, "signals"),
+			}),
+		})
+	}
+
+	testCases := map[string]struct {
+		existingAction string
+		storeToken     bool
+		expectedError  string
+		expectedTokens int
+	}{
+		"token created before the failure": {
+			storeToken:     true,
+			expectedError:  "Unable to confirm token create",
+			expectedTokens: 1,
+		},
+		"token not created": {
+			expectedError: "Error creating token",
+		},
+		"other token with the description": {
+			existingAction: "read",
+			expectedError:  "Error creating token",
+			expectedTokens: 1,
+		},
+		"identical token": {
+			existingAction: "write",
+			expectedError:  "Unable to confirm token create",
+			expectedTokens: 1,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.addDatabase(map[string]any{"name": "signals"})
+			if testCase.existingAction != "" {
+				api.addToken(map[string]any{
+					"description": "signals ingest",
+					"permissions": []map[string]any{{"action": testCase.existingAction, "resource": "signals"}},
+				})
+			}
+			api.intercept = func(w http.ResponseWriter, r *http.Request, resource string) bool {
+				if r.Method != http.MethodPost {
+					return true
+				}
+				if testCase.storeToken {
+					api.serve(httptest.NewRecorder(), r, resource)
+				}
+				writeFakeError(w, http.StatusInternalServerError, "internal error")
+				return false
+			}
+
+			r := &TokenResource{
+				client:        api.client,
+				accountID:     api.accountID,
+				clusterID:     api.clusterID,
+				databaseCache: newDatabaseCache(api.client, api.accountID, api.clusterID),
+			}
+			plan := testResourceObject(t, r, map[string]tftypes.Value{
+				"description": tftypes.NewValue(tftypes.String, "signals ingest"),
+				"permissions": permissions("write"),
+			})
+			resp := &resource.CreateResponse{State: testResourceObject(t, r, nil)}
+			r.Create(t.Context(), resource.CreateRequest{Plan: tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw}}, resp)
+
+			testCheckDiagnosticSummaries(t, resp.Diagnostics, testCase.expectedError, "")
+			if tokens := api.tokenList(); len(tokens) != testCase.expectedTokens {
+				t.Errorf("expected %d tokens, got %v", testCase.expectedTokens, tokens)
+			}
+			for _, request := range api.requestLog() {
+				if strings.HasPrefix(request, http.MethodPatch) || strings.HasPrefix(request, http.MethodDelete) {
+					t.Errorf("unexpected request %s", request)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenResourceCreate(t *testing.T) {
+	api := newFakeAPI(t)
+	api.addDatabase(map[string]any{"name": "signals"})
+
+	r := &TokenResource{
+		client:        api.client,
+		accountID:     api.accountID,
+		clusterID:     api.clusterID,
+		databaseCache: newDatabaseCache(api.client, api.accountID, api.clusterID),
+	}
+	plan := testResourceObject(t, r, map[string]tftypes.Value{
+		"description":    tftypes.NewValue(tftypes.String, "signals ingest"),
+		"read_databases": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "signals")}),
+	})
+	resp := &resource.CreateResponse{State: testResourceObject(t, r, nil)}
+	r.Create(t.Context(), resource.CreateRequest{Plan: tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw}}, resp)
+	testCheckDiagnosticSummaries(t, resp.Diagnostics, "", "")
+
+	var state TokenResourceModel
+	resp.Diagnostics.Append(resp.State.Get(t.Context(), &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	tokens := api.tokenList()
+	if len(tokens) != 1 || tokens[0]["description"] != "signals ingest" {
+		t.Fatalf("expected a single token with the planned description, got %v", tokens)
+	}
+	if state.Id.ValueString() != tokens[0]["id"] || state.AccessToken.IsNull() {
+		t.Errorf("expected the state of token %v, got %s", tokens[0]["id"], state.Id)
+	}
+}
+
+func TestTokenResourceCreateRetried(t *testing.T) {
+	permissionType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"action": tftypes.String, "resource": tftypes.String}}
+
+	testCases := map[string]struct {
+		storeToken      bool
+		expectedWarning string
+		expectedTokens  int
+	}{
+		"token created before the failure": {
+			storeToken:      true,
+			expectedWarning: "Possible duplicate database tokens",
+			expectedTokens:  2,
+		},
+		"token not created": {
+			expectedTokens: 1,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.addDatabase(map[string]any{"name": "signals"})
+			posts := 0
+			api.intercept = func(w http.ResponseWriter, r *http.Request, resource string) bool {
+				if r.Method != http.MethodPost {
+					return true
+				}
+				posts++
+				if posts > 1 {
+					return true
+				}
+				if testCase.storeToken {
+					api.serve(httptest.NewRecorder(), r, resource)
+				}
+				writeFakeError(w, http.StatusInternalServerError, "internal error")
+				return false
+			}
+
+			client := api.retryingClient(t)
+			r := &TokenResource{
+				client:        client,
+				accountID:     api.accountID,
+				clusterID:     api.clusterID,
+				databaseCache: newDatabaseCache(client, api.accountID, api.clusterID),
+			}
+			plan := testResourceObject(t, r, map[string]tftypes.Value{
+				"description": tftypes.NewValue(tftypes.String, "signals ingest"),
+				"permissions": tftypes.NewValue(tftypes.Set{ElementType: permissionType}, []tftypes.Value{
+					tftypes.NewValue(permissionType, map[string]tftypes.Value{
+						"action":   tftypes.NewValue(tftypes.String, "write"),
+						"resource": tftypes.NewValue(tftypes.String, "signals"),
+					}),
+				}),
+			})
+			resp := &resource.CreateResponse{State: testResourceObject(t, r, nil)}
+			r.Create(t.Context(), resource.CreateRequest{Plan: tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw}}, resp)
+
+			testCheckDiagnosticSummaries(t, resp.Diagnostics, "", testCase.expectedWarning)
+			if testCase.expectedWarning == "" && resp.Diagnostics.WarningsCount() != 0 {
+				t.Errorf("unexpected warnings: %v", resp.Diagnostics.Warnings())
+			}
+			if posts != 2 {
+				t.Errorf("expected the create to be retried once, got %d requests", posts)
+			}
+			if tokens := api.tokenList(); len(tokens) != testCase.expectedTokens {
+				t.Errorf("expected %d tokens, got %v", testCase.expectedTokens, tokens)
+			}
+
+			var state TokenResourceModel
+			resp.Diagnostics.Append(resp.State.Get(t.Context(), &state)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			if state.AccessToken.ValueString() == "" {
+				t.Error("expected the access token of the created token")
+			}
+		})
+	}
+}
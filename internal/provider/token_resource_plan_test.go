@@ -0,0 +1,376 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestTokenResourceModifyPlanExpiry(t *testing.T) {
+	str := func(value string) tftypes.Value {
+		return tftypes.NewValue(tftypes.String, value)
+	}
+	in := func(d time.Duration) string {
+		return time.Now().UTC().Add(d).Truncate(time.Second).Format(time.RFC3339)
+	}
+	token := func(expiresAt string, expiresIn string) map[string]tftypes.Value {
+		state := map[string]tftypes.Value{
+			"description": str("signals"),
+			"id":          str("e4d1a8c2-3f5b-4a7e-9c6d-1b2a3c4d5e6f"),
+			"expires_at":  str(expiresAt),
+		}
+		if expiresIn != "" {
+			state["expires_in"] = str(expiresIn)
+		}
+		return state
+	}
+	inFiveHundredHours := in(500 * time.Hour)
+	inHundredHours := in(100 * time.Hour)
+	anHourAgo := in(-time.Hour)
+
+	testCases := map[string]struct {
+		config            map[string]tftypes.Value
+		state             map[string]tftypes.Value
+		expectedExpiresAt types.String
+		expectedReplace   bool
+		expectedError     string
+		expectedWarning   string
+	}{
+		"create with expires_in": {
+			config:            map[string]tftypes.Value{"description": str("signals"), "expires_in": str("720h")},
+			expectedExpiresAt: types.StringUnknown(),
+		},
+		"unchanged expires_in": {
+			config:            map[string]tftypes.Value{"description": str("signals"), "expires_in": str("720h")},
+			state:             token(inFiveHundredHours, "720h"),
+			expectedExpiresAt: types.StringValue(inFiveHundredHours),
+		},
+		"unchanged expires_at": {
+			config:            map[string]tftypes.Value{"description": str("signals"), "expires_at": str(inFiveHundredHours)},
+			state:             token(inFiveHundredHours, ""),
+			expectedExpiresAt: types.StringValue(inFiveHundredHours),
+		},
+		"rotate_before not reached": {
+			config:            map[string]tftypes.Value{"description": str("signals"), "expires_in": str("720h"), "rotate_before": str("168h")},
+			state:             token(inFiveHundredHours, "720h"),
+			expectedExpiresAt: types.StringValue(inFiveHundredHours),
+		},
+		"rotate_before reached": {
+			config:            map[string]tftypes.Value{"description": str("signals"), "expires_in": str("720h"), "rotate_before": str("168h")},
+			state:             token(inHundredHours, "720h"),
+			expectedExpiresAt: types.StringUnknown(),
+			expectedReplace:   true,
+			expectedWarning:   "Database token will be rotated",
+		},
+		"changed expires_in": {
+			config:            map[string]tftypes.Value{"description": str("signals"), "expires_in": str("1440h")},
+			state:             token(inFiveHundredHours, "720h"),
+			expectedExpiresAt: types.StringUnknown(),
+			expectedReplace:   true,
+		},
+		"changed expires_at": {
+			config:            map[string]tftypes.Value{"description": str("signals"), "expires_at": str(inHundredHours)},
+			state:             token(inFiveHundredHours, ""),
+			expectedExpiresAt: types.StringValue(inHundredHours),
+			expectedReplace:   true,
+		},
+		"removed expiry": {
+			config:            map[string]tftypes.Value{"description": str("signals")},
+			state:             token(inFiveHundredHours, ""),
+			expectedExpiresAt: types.StringNull(),
+			expectedReplace:   true,
+		},
+		"expired": {
+			config:            map[string]tftypes.Value{"description": str("signals"), "expires_at": str(anHourAgo)},
+			state:             token(anHourAgo, ""),
+			expectedExpiresAt: types.StringValue(anHourAgo),
+			expectedError:     "Database token has expired",
+		},
+		"expires soon": {
+			config:            map[string]tftypes.Value{"description": str("signals"), "expires_in": str("720h")},
+			state:             token(inHundredHours, "720h"),
+			expectedExpiresAt: types.StringValue(inHundredHours),
+			expectedWarning:   "Database token expires soon",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := &TokenResource{expiryWarningWindow: 336 * time.Hour}
+			resp := testModifyPlan(t, r, testCase.config, testCase.state)
+
+			testCheckDiagnosticSummaries(t, resp.Diagnostics, testCase.expectedError, testCase.expectedWarning)
+			if testCase.expectedError != "" {
+				return
+			}
+
+			var expiresAt types.String
+			resp.Diagnostics.Append(resp.Plan.GetAttribute(t.Context(), path.Root("expires_at"), &expiresAt)...)
+			if !expiresAt.Equal(testCase.expectedExpiresAt) {
+				t.Errorf("expected expires_at %s, got %s", testCase.expectedExpiresAt, expiresAt)
+			}
+
+			replace := false
+			for _, p := range resp.RequiresReplace {
+				replace = replace || p.Equal(path.Root("expires_at"))
+			}
+			if replace != testCase.expectedReplace {
+				t.Errorf("expected replacement %t, got %t", testCase.expectedReplace, replace)
+			}
+		})
+	}
+}
+
+func TestTokenResourceModifyPlanPermissionDatabases(t *testing.T) {
+	permissionType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"action": tftypes.String, "resource": tftypes.String}}
+	permissions := func(databases ...string) tftypes.Value {
+		var elements []tftypes.Value
+		for _, database := range databases {
+			elements = append(elements, tftypes.NewValue(permissionType, map[string]tftypes.Value{
+				"action":   tftypes.NewValue(tftypes.String, "read"),
+				"resource": tftypes.NewValue(tftypes.String, database),
+			}))
+		}
+		return tftypes.NewValue(tftypes.Set{ElementType: permissionType}, elements)
+	}
+
+	testCases := map[string]struct {
+		permissions       tftypes.Value
+		namespace         databaseNamespace
+		strictPermissions bool
+		expectedError     string
+		expectedWarning   string
+	}{
+		"existing database": {
+			permissions: permissions("signals"),
+		},
+		"all databases": {
+			permissions: permissions("*"),
+		},
+		"unknown database": {
+			permissions:     permissions("signals", "metrics"),
+			expectedWarning: "Permission names an unknown database",
+		},
+		"unknown database with strict_permissions": {
+			permissions:       permissions("metrics"),
+			strictPermissions: true,
+			expectedError:     "Permission names an unknown database",
+		},
+		"database known during apply with strict_permissions": {
+			permissions: tftypes.NewValue(tftypes.Set{ElementType: permissionType}, []tftypes.Value{
+				tftypes.NewValue(permissionType, map[string]tftypes.Value{
+					"action":   tftypes.NewValue(tftypes.String, "read"),
+					"resource": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				}),
+			}),
+			strictPermissions: true,
+		},
+		"existing database with namespace": {
+			permissions: permissions("signals"),
+			namespace:   "team-",
+		},
+		"all databases with namespace": {
+			permissions:   permissions("*"),
+			namespace:     "team-",
+			expectedError: "Permission on all databases outside the namespace",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.addDatabase(map[string]any{"name": testCase.namespace.qualify("signals")})
+
+			r := &TokenResource{
+				client:            api.client,
+				accountID:         api.accountID,
+				clusterID:         api.clusterID,
+				databaseCache:     newDatabaseCache(api.client, api.accountID, api.clusterID),
+				namespace:         testCase.namespace,
+				strictPermissions: testCase.strictPermissions,
+			}
+			resp := testModifyPlan(t, r, map[string]tftypes.Value{
+				"description": tftypes.NewValue(tftypes.String, "signals"),
+				"permissions": testCase.permissions,
+			}, nil)
+
+			testCheckDiagnosticSummaries(t, resp.Diagnostics, testCase.expectedError, testCase.expectedWarning)
+			if testCase.expectedWarning == "" && resp.Diagnostics.WarningsCount() != 0 {
+				t.Errorf("unexpected warnings: %v", resp.Diagnostics.Warnings())
+			}
+		})
+	}
+}
+
+func TestTokenResourceCheckPermissionDatabases(t *testing.T) {
+	permission := func(database string) TokenPermissionModel {
+		return TokenPermissionModel{Action: types.StringValue("write"), Resource: types.StringValue(database)}
+	}
+
+	testCases := map[string]struct {
+		permissions       []TokenPermissionModel
+		strictPermissions bool
+		expectedError     string
+	}{
+		"unknown database": {
+			permissions: []TokenPermissionModel{permission("metrics")},
+		},
+		"existing database with strict_permissions": {
+			permissions:       []TokenPermissionModel{permission("signals"), permission("*")},
+			strictPermissions: true,
+		},
+		"unknown database with strict_permissions": {
+			permissions:       []TokenPermissionModel{permission("signals"), permission("metrics")},
+			strictPermissions: true,
+			expectedError:     "Permission names an unknown database",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.addDatabase(map[string]any{"name": "signals"})
+
+			r := &TokenResource{
+				databaseCache:     newDatabaseCache(api.client, api.accountID, api.clusterID),
+				strictPermissions: testCase.strictPermissions,
+			}
+			diags := r.checkPermissionDatabases(t.Context(), testCase.permissions)
+
+			testCheckDiagnosticSummaries(t, diags, testCase.expectedError, "")
+		})
+	}
+}
+
+func TestTokenResourceModifyPlanPolicy(t *testing.T) {
+	str := func(value string) tftypes.Value {
+		return tftypes.NewValue(tftypes.String, value)
+	}
+	at := func(d time.Duration) string {
+		return time.Now().UTC().Add(d).Truncate(time.Second).Format(time.RFC3339)
+	}
+	inFourHundredHours := at(400 * time.Hour)
+
+	testCases := map[string]struct {
+		config        map[string]tftypes.Value
+		state         map[string]tftypes.Value
+		expectedError string
+	}{
+		"new token within lifetime": {
+			config: map[string]tftypes.Value{"description": str("signals"), "expires_in": str("168h")},
+		},
+		"new token too long": {
+			config:        map[string]tftypes.Value{"description": str("signals"), "expires_in": str("1000h")},
+			expectedError: "Provider Policy Violation",
+		},
+		"new token without expiry": {
+			config:        map[string]tftypes.Value{"description": str("signals")},
+			expectedError: "Provider Policy Violation",
+		},
+		"existing token within lifetime": {
+			config: map[string]tftypes.Value{"description": str("signals"), "expires_at": str(inFourHundredHours)},
+			state: map[string]tftypes.Value{
+				"description": str("signals"),
+				"created_at":  str(at(-200 * time.Hour)),
+				"expires_at":  str(inFourHundredHours),
+			},
+		},
+		"existing token too long": {
+			config: map[string]tftypes.Value{"description": str("signals"), "expires_at": str(inFourHundredHours)},
+			state: map[string]tftypes.Value{
+				"description": str("signals"),
+				"created_at":  str(at(-500 * time.Hour)),
+				"expires_at":  str(inFourHundredHours),
+			},
+			expectedError: "Provider Policy Violation",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			policy, diags := newProviderPolicy(&PolicyModel{MaxTokenLifetime: types.StringValue("720h")})
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			r := &TokenResource{policy: policy}
+			resp := testModifyPlan(t, r, testCase.config, testCase.state)
+
+			testCheckDiagnosticSummaries(t, resp.Diagnostics, testCase.expectedError, "")
+		})
+	}
+}
+
+func TestTokenResourceModifyPlanUnknownPermission(t *testing.T) {
+	permissionType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"action": tftypes.String, "resource": tftypes.String}}
+	permission := func(action string, resource any) tftypes.Value {
+		return tftypes.NewValue(permissionType, map[string]tftypes.Value{
+			"action":   tftypes.NewValue(tftypes.String, action),
+			"resource": tftypes.NewValue(tftypes.String, resource),
+		})
+	}
+
+	testCases := map[string]struct {
+		permissions   []tftypes.Value
+		policy        *PolicyModel
+		expectedError string
+	}{
+		"unknown resource": {
+			permissions: []tftypes.Value{permission("read", "signals"), permission("read", tftypes.UnknownValue)},
+		},
+		"unknown permission": {
+			permissions: []tftypes.Value{permission("read", "signals"), tftypes.NewValue(permissionType, tftypes.UnknownValue)},
+		},
+		"known permissions are checked against the policy": {
+			permissions:   []tftypes.Value{permission("write", "*"), permission("read", tftypes.UnknownValue)},
+			policy:        &PolicyModel{ForbidWildcardWrite: types.BoolValue(true)},
+			expectedError: "Provider Policy Violation",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.addDatabase(map[string]any{"name": "signals"})
+
+			r := &TokenResource{
+				client:        api.client,
+				accountID:     api.accountID,
+				clusterID:     api.clusterID,
+				databaseCache: newDatabaseCache(api.client, api.accountID, api.clusterID),
+			}
+			if testCase.policy != nil {
+				policy, diags := newProviderPolicy(testCase.policy)
+				if diags.HasError() {
+					t.Fatalf("unexpected error: %v", diags)
+				}
+				r.policy = policy
+			}
+			resp := testModifyPlan(t, r, map[string]tftypes.Value{
+				"description": tftypes.NewValue(tftypes.String, "signals"),
+				"permissions": tftypes.NewValue(tftypes.Set{ElementType: permissionType}, testCase.permissions),
+			}, nil)
+
+			testCheckDiagnosticSummaries(t, resp.Diagnostics, testCase.expectedError, "")
+			if resp.Diagnostics.WarningsCount() != 0 {
+				t.Errorf("unexpected warnings: %v", resp.Diagnostics.Warnings())
+			}
+			if testCase.expectedError != "" {
+				return
+			}
+
+			var readDatabases, writeDatabases types.Set
+			resp.Diagnostics.Append(resp.Plan.GetAttribute(t.Context(), path.Root("read_databases"), &readDatabases)...)
+			resp.Diagnostics.Append(resp.Plan.GetAttribute(t.Context(), path.Root("write_databases"), &writeDatabases)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			if !readDatabases.IsUnknown() || !writeDatabases.IsUnknown() {
+				t.Errorf("expected read_databases and write_databases to be unknown, got %s and %s", readDatabases, writeDatabases)
+			}
+		})
+	}
+}
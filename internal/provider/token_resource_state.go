@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// TOKEN_RESOURCE_SCHEMA_VERSION is the current schema version of the influxdb3_token resource.
+const TOKEN_RESOURCE_SCHEMA_VERSION = 1
+
+// tokenModelV0 maps version 0 state of the influxdb3_token resource.
+type tokenModelV0 struct {
+	AccessToken types.String           `tfsdk:"access_token"`
+	AccountId   types.String           `tfsdk:"account_id"`
+	CreatedAt   types.String           `tfsdk:"created_at"`
+	ClusterId   types.String           `tfsdk:"cluster_id"`
+	Description types.String           `tfsdk:"description"`
+	ExpiresAt   types.String           `tfsdk:"expires_at"`
+	Id          types.String           `tfsdk:"id"`
+	Permissions []TokenPermissionModel `tfsdk:"permissions"`
+}
+
+// upgrade converts version 0 state into the current TokenResourceModel.
+// Permissions were stored as a list and are now a set, so duplicate entries
+// are dropped.
+func (m tokenModelV0) upgrade() TokenResourceModel {
+	permissions := []TokenPermissionModel{}
+	seen := make(map[TokenPermissionModel]bool)
+	for _, permission := range m.Permissions {
+		if seen[permission] {
+			continue
+		}
+		seen[permission] = true
+		permissions = append(permissions, permission)
+	}
+
+	state := TokenResourceModel{
+		AccessToken:      m.AccessToken,
+		AccountId:        m.AccountId,
+		CreatedAt:        m.CreatedAt,
+		ClusterId:        m.ClusterId,
+		Description:      m.Description,
+		ExpiresAt:        m.ExpiresAt,
+		ExpiresIn:        types.StringNull(),
+		ExpiresInSeconds: types.Int64Null(),
+		Id:               m.Id,
+		IsExpired:        types.BoolNull(),
+		RotateBefore:     types.StringNull(),
+	}
+	state.setPermissions(permissions)
+	return state
+}
+
+// upgradeTokenStateV0 upgrades version 0 state to the current schema version.
+func upgradeTokenStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var priorState tokenModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &priorState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state := priorState.upgrade()
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// tokenResourceSchemaV0 is the version 0 schema of the influxdb3_token
+// resource. It is the schema published under the komminarlabs/influxdb3
+// namespace and is kept to decode state written by those releases.
+func tokenResourceSchemaV0() schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"access_token": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+			},
+			"account_id": schema.StringAttribute{
+				Computed: true,
+			},
+			"created_at": schema.StringAttribute{
+				Computed: true,
+			},
+			"cluster_id": schema.StringAttribute{
+				Computed: true,
+			},
+			"description": schema.StringAttribute{
+				Required: true,
+			},
+			"expires_at": schema.StringAttribute{
+				Optional: true,
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"permissions": schema.ListNestedAttribute{
+				Required: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"action": schema.StringAttribute{
+							Required: true,
+						},
+						"resource": schema.StringAttribute{
+							Required: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
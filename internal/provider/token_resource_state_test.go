@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestTokenResourceUpgradeState(t *testing.T) {
+	testCases := map[string]struct {
+		version     int64
+		fixture     string
+		expected    TokenResourceModel
+		permissions [][2]string
+	}{
+		"v1.5.0": {
+			version:     0,
+			fixture:     "token_state_v0.json",
+			expected:    testTokenResourceModel("e4d1a8c2-3f5b-4a7e-9c6d-1b2a3c4d5e6f", "Access signals database", "2025-11-06T09:14:27.532918Z", "2026-11-06T00:00:00Z"),
+			permissions: [][2]string{{"read", "signals"}, {"write", "signals"}},
+		},
+		"komminarlabs": {
+			version:     0,
+			fixture:     "token_state_v0_komminarlabs.json",
+			expected:    testTokenResourceModel("7a6b5c4d-3e2f-4a1b-8c9d-0e1f2a3b4c5d", "Read all databases", "2024-09-12T15:02:11Z", ""),
+			permissions: [][2]string{{"read", "*"}},
+		},
+		"duplicate permissions": {
+			version:     0,
+			fixture:     "token_state_v0_duplicates.json",
+			expected:    testTokenResourceModel("3c2b1a0f-9e8d-4c6b-a5e4-d3c2b1a0f9e8", "Write telemetry", "2026-01-20T08:30:00.120045Z", ""),
+			permissions: [][2]string{{"read", "telemetry"}, {"write", "telemetry"}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			state := testUpgradeResourceState(t, NewTokenResource(), "influxdb3_token", testCase.version, testCase.fixture)
+			testCheckTokenState(t, state, testCase.expected, testCase.permissions)
+		})
+	}
+}
+
+func TestTokenResourceMoveStateFromLegacyProvider(t *testing.T) {
+	state := testMoveResourceState(t, NewTokenResource(), "registry.terraform.io/komminarlabs/influxdb3", "influxdb3_token", "token_state_v0_komminarlabs.json")
+	testCheckTokenState(t, state, testTokenResourceModel("7a6b5c4d-3e2f-4a1b-8c9d-0e1f2a3b4c5d", "Read all databases", "2024-09-12T15:02:11Z", ""), [][2]string{{"read", "*"}})
+}
+
+func testTokenResourceModel(id string, description string, createdAt string, expiresAt string) TokenResourceModel {
+	token := TokenResourceModel{
+		Id:          types.StringValue(id),
+		Description: types.StringValue(description),
+		CreatedAt:   types.StringValue(createdAt),
+		ExpiresAt:   types.StringNull(),
+	}
+	if expiresAt != "" {
+		token.ExpiresAt = types.StringValue(expiresAt)
+	}
+	return token
+}
+
+func testCheckTokenState(t *testing.T, state tfsdk.State, expected TokenResourceModel, permissions [][2]string) {
+	t.Helper()
+
+	var token TokenResourceModel
+	if diags := state.Get(t.Context(), &token); diags.HasError() {
+		t.Fatalf("unable to read upgraded state: %v", diags)
+	}
+
+	if !token.Id.Equal(expected.Id) {
+		t.Errorf("expected id %s, got %s", expected.Id, token.Id)
+	}
+	if !token.Description.Equal(expected.Description) {
+		t.Errorf("expected description %s, got %s", expected.Description, token.Description)
+	}
+	if !token.CreatedAt.Equal(expected.CreatedAt) {
+		t.Errorf("expected created_at %s, got %s", expected.CreatedAt, token.CreatedAt)
+	}
+	if !token.ExpiresAt.Equal(expected.ExpiresAt) {
+		t.Errorf("expected expires_at %s, got %s", expected.ExpiresAt, token.ExpiresAt)
+	}
+	if token.AccessToken.IsNull() {
+		t.Error("expected access_token to be carried over")
+	}
+
+	migratedPermissions, known := tokenPermissions(token.Permissions)
+	if !known || len(migratedPermissions) != len(permissions) {
+		t.Fatalf("expected %d permissions, got %d", len(permissions), len(migratedPermissions))
+	}
+
+	readDatabases, writeDatabases := getDatabasePermissions(migratedPermissions)
+	if !token.ReadDatabases.Equal(readDatabases) || !token.WriteDatabases.Equal(writeDatabases) {
+		t.Errorf("expected read_databases %s and write_databases %s, got %s and %s", readDatabases, writeDatabases, token.ReadDatabases, token.WriteDatabases)
+	}
+
+	actual := make(map[[2]string]bool)
+	for _, permission := range migratedPermissions {
+		actual[[2]string{permission.Action.ValueString(), permission.Resource.ValueString()}] = true
+	}
+	for _, expected := range permissions {
+		if !actual[expected] {
+			t.Errorf("expected permission %s on %s", expected[0], expected[1])
+		}
+	}
+}
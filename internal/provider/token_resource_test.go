@@ -19,6 +19,7 @@ func TestAccTokenResource(t *testing.T) {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("influxdb3_token.test", "permissions.#", "2"),
 					resource.TestCheckResourceAttr("influxdb3_token.test", "description", "Access test bucket"),
+					resource.TestCheckResourceAttr("influxdb3_token.test", "is_expired", "false"),
 				),
 			},
 			// ImportState testing
@@ -3,10 +3,13 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/thulasirajkomminar/influxdb3-management-go"
 )
@@ -27,11 +30,19 @@ type TokensDataSource struct {
 	accountID influxdb3.UuidV4
 	client    influxdb3.ClientWithResponses
 	clusterID influxdb3.UuidV4
+	namespace databaseNamespace
 }
 
 // TokensDataSourceModel describes the data source data model.
 type TokensDataSourceModel struct {
-	Tokens []TokenModel `tfsdk:"tokens"`
+	Action           types.String              `tfsdk:"action"`
+	Database         types.String              `tfsdk:"database"`
+	DescriptionRegex types.String              `tfsdk:"description_regex"`
+	Expired          types.Bool                `tfsdk:"expired"`
+	Ids              []types.String            `tfsdk:"ids"`
+	IdsByDescription map[string][]types.String `tfsdk:"ids_by_description"`
+	Tokens           []TokenModel              `tfsdk:"tokens"`
+	TokensById       map[string]TokenModel     `tfsdk:"tokens_by_id"`
 }
 
 // Metadata returns the data source type name.
@@ -43,58 +54,111 @@ func (d *TokensDataSource) Metadata(ctx context.Context, req datasource.Metadata
 func (d *TokensDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
-		Description: "Gets all database tokens for a cluster.",
+		Description: "Gets all database tokens for a cluster, optionally filtered.",
 
 		Attributes: map[string]schema.Attribute{
+			"action": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return database tokens with a permission that allows this action. Valid values are `read` or `write`. Combined with `database`, the same permission must match both.",
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{"read", "write"}...),
+				},
+			},
+			"database": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return database tokens with a permission on this database. Permissions on all databases (`*`) match any database.",
+			},
+			"description_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return database tokens whose description matches this regular expression.",
+				Validators: []validator.String{
+					regexpValidator{},
+				},
+			},
+			"expired": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Only return database tokens that have expired when `true`, or that have not when `false`.",
+			},
+			"ids": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The IDs of the database tokens.",
+			},
+			"ids_by_description": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.ListType{ElemType: types.StringType},
+				Description: "The IDs of the database tokens keyed by description. Descriptions are not unique, so each description maps to the IDs of all database tokens with that description.",
+			},
 			"tokens": schema.ListNestedAttribute{
 				Computed: true,
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"access_token": schema.StringAttribute{
-							Computed:    true,
-							Description: "The access token that can be used to authenticate query and write requests to the cluster. The access token is never stored by InfluxDB and is only returned once when the token is created. If the access token is lost, a new token must be created.",
-							Sensitive:   true,
-						},
-						"account_id": schema.StringAttribute{
-							Computed:    true,
-							Description: "The ID of the account that the database token belongs to.",
-						},
-						"created_at": schema.StringAttribute{
-							Computed:    true,
-							Description: "The date and time that the database token was created. Uses RFC3339 format.",
-						},
-						"cluster_id": schema.StringAttribute{
-							Computed:    true,
-							Description: "The ID of the cluster that the database token belongs to.",
-						},
-						"description": schema.StringAttribute{
-							Computed:    true,
-							Description: "The description of the database token.",
-						},
-						"expires_at": schema.StringAttribute{
-							Computed:    true,
-							Description: "The date and time that the database token expires, if applicable. Uses RFC3339 format.",
-						},
-						"id": schema.StringAttribute{
-							Computed:    true,
-							Description: "The ID of the database token.",
-						},
-						"permissions": schema.ListNestedAttribute{
-							Computed:    true,
-							Description: "The list of permissions the database token allows.",
-							NestedObject: schema.NestedAttributeObject{
-								Attributes: map[string]schema.Attribute{
-									"action": schema.StringAttribute{
-										Computed:    true,
-										Description: "The action the database token permission allows.",
-									},
-									"resource": schema.StringAttribute{
-										Computed:    true,
-										Description: "The resource the database token permission applies to. `*` refers to all databases.",
-									},
-								},
-							},
-						},
+					Attributes: tokenDataSourceAttributes(),
+				},
+			},
+			"tokens_by_id": schema.MapNestedAttribute{
+				Computed:    true,
+				Description: "The database tokens keyed by ID.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: tokenDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+// tokenDataSourceAttributes returns the attributes of a database token
+// returned by the data source.
+func tokenDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"access_token": schema.StringAttribute{
+			Computed:    true,
+			Description: "The access token that can be used to authenticate query and write requests to the cluster. The access token is never stored by InfluxDB and is only returned once when the token is created. If the access token is lost, a new token must be created.",
+			Sensitive:   true,
+		},
+		"account_id": schema.StringAttribute{
+			Computed:    true,
+			Description: "The ID of the account that the database token belongs to.",
+		},
+		"created_at": schema.StringAttribute{
+			Computed:    true,
+			Description: "The date and time that the database token was created. Uses RFC3339 format.",
+		},
+		"cluster_id": schema.StringAttribute{
+			Computed:    true,
+			Description: "The ID of the cluster that the database token belongs to.",
+		},
+		"description": schema.StringAttribute{
+			Computed:    true,
+			Description: "The description of the database token.",
+		},
+		"expires_at": schema.StringAttribute{
+			Computed:    true,
+			Description: "The date and time that the database token expires, if applicable. Uses RFC3339 format.",
+		},
+		"expires_in_seconds": schema.Int64Attribute{
+			Computed:    true,
+			Description: "The number of seconds until the database token expires, if applicable. `0` once the token has expired.",
+		},
+		"id": schema.StringAttribute{
+			Computed:    true,
+			Description: "The ID of the database token.",
+		},
+		"is_expired": schema.BoolAttribute{
+			Computed:    true,
+			Description: "Whether the database token has expired.",
+		},
+		"permissions": schema.ListNestedAttribute{
+			Computed:    true,
+			Description: "The list of permissions the database token allows.",
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"action": schema.StringAttribute{
+						Computed:    true,
+						Description: "The action the database token permission allows.",
+					},
+					"resource": schema.StringAttribute{
+						Computed:    true,
+						Description: "The resource the database token permission applies to. `*` refers to all databases.",
 					},
 				},
 			},
@@ -121,6 +185,7 @@ func (d *TokensDataSource) Configure(ctx context.Context, req datasource.Configu
 	d.accountID = pd.accountID
 	d.client = pd.client
 	d.clusterID = pd.clusterID
+	d.namespace = pd.namespace
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -157,22 +222,47 @@ func (d *TokensDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
+	var descriptionRegex *regexp.Regexp
+	if !state.DescriptionRegex.IsNull() {
+		descriptionRegex, err = regexp.Compile(state.DescriptionRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error getting tokens",
+				err.Error(),
+			)
+			return
+		}
+	}
+
 	// Map response body to model
+	state.Ids = []types.String{}
+	state.IdsByDescription = map[string][]types.String{}
+	state.Tokens = []TokenModel{}
+	state.TokensById = map[string]TokenModel{}
 	for _, token := range *readTokensResponse.JSON200 {
+		permissions, ok := d.namespace.unqualifyPermissions(getPermissions(token.Permissions))
+		if !ok {
+			continue
+		}
+
 		tokenState := TokenModel{
 			AccountId:   types.StringValue(token.AccountId.String()),
 			CreatedAt:   types.StringValue(token.CreatedAt.Format(time.RFC3339Nano)),
 			ClusterId:   types.StringValue(token.ClusterId.String()),
 			Description: types.StringValue(token.Description),
 			Id:          types.StringValue(token.Id.String()),
-			Permissions: getPermissions(token.Permissions),
+			Permissions: permissions,
 		}
 
-		if token.ExpiresAt != nil {
-			tokenState.ExpiresAt = types.StringValue(token.ExpiresAt.Format(time.RFC3339))
+		tokenState.setExpiry(token.ExpiresAt)
+		if !state.matches(tokenState, descriptionRegex) {
+			continue
 		}
 
+		state.Ids = append(state.Ids, tokenState.Id)
+		state.IdsByDescription[token.Description] = append(state.IdsByDescription[token.Description], tokenState.Id)
 		state.Tokens = append(state.Tokens, tokenState)
+		state.TokensById[tokenState.Id.ValueString()] = tokenState
 	}
 
 	// Set state
@@ -182,3 +272,30 @@ func (d *TokensDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 }
+
+// matches reports whether the token passes the filters of the data source.
+func (m TokensDataSourceModel) matches(token TokenModel, descriptionRegex *regexp.Regexp) bool {
+	if descriptionRegex != nil && !descriptionRegex.MatchString(token.Description.ValueString()) {
+		return false
+	}
+
+	if !m.Expired.IsNull() && token.IsExpired.ValueBool() != m.Expired.ValueBool() {
+		return false
+	}
+
+	if m.Database.IsNull() && m.Action.IsNull() {
+		return true
+	}
+
+	for _, permission := range token.Permissions {
+		resource := permission.Resource.ValueString()
+		if !m.Database.IsNull() && resource != m.Database.ValueString() && resource != "*" {
+			continue
+		}
+		if !m.Action.IsNull() && permission.Action.ValueString() != m.Action.ValueString() {
+			continue
+		}
+		return true
+	}
+	return false
+}
@@ -1,8 +1,14 @@
 package provider
 
 import (
+	"regexp"
+	"slices"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -25,3 +31,111 @@ func TestAccTokensDataSource(t *testing.T) {
 const testAccTokensDataSourceConfig = `
 data "influxdb3_tokens" "all" {}
 `
+
+func TestTokensDataSourceModelMatches(t *testing.T) {
+	token := TokenModel{
+		Description: types.StringValue("signals ingest"),
+		IsExpired:   types.BoolValue(false),
+		Permissions: []TokenPermissionModel{
+			{Action: types.StringValue("read"), Resource: types.StringValue("*")},
+			{Action: types.StringValue("write"), Resource: types.StringValue("signals")},
+		},
+	}
+
+	testCases := map[string]struct {
+		filters          TokensDataSourceModel
+		descriptionRegex *regexp.Regexp
+		expected         bool
+	}{
+		"no-filters": {
+			expected: true,
+		},
+		"description-regex": {
+			descriptionRegex: regexp.MustCompile("^signals"),
+			expected:         true,
+		},
+		"description-regex-mismatch": {
+			descriptionRegex: regexp.MustCompile("^telemetry"),
+			expected:         false,
+		},
+		"database": {
+			filters:  TokensDataSourceModel{Database: types.StringValue("signals")},
+			expected: true,
+		},
+		"database-wildcard": {
+			filters:  TokensDataSourceModel{Database: types.StringValue("telemetry")},
+			expected: true,
+		},
+		"database-action": {
+			filters:  TokensDataSourceModel{Action: types.StringValue("write"), Database: types.StringValue("signals")},
+			expected: true,
+		},
+		"database-action-mismatch": {
+			filters:  TokensDataSourceModel{Action: types.StringValue("write"), Database: types.StringValue("telemetry")},
+			expected: false,
+		},
+		"expired": {
+			filters:  TokensDataSourceModel{Expired: types.BoolValue(true)},
+			expected: false,
+		},
+		"not-expired": {
+			filters:  TokensDataSourceModel{Expired: types.BoolValue(false)},
+			expected: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := testCase.filters.matches(token, testCase.descriptionRegex)
+			if got != testCase.expected {
+				t.Errorf("expected %t, got %t", testCase.expected, got)
+			}
+		})
+	}
+}
+
+func TestTokensDataSourceReadIdsByDescription(t *testing.T) {
+	api := newFakeAPI(t)
+	permissions := []map[string]any{{"action": "write", "resource": "signals"}}
+	firstIngestId := api.addToken(map[string]any{"description": "signals ingest", "permissions": permissions})
+	secondIngestId := api.addToken(map[string]any{"description": "signals ingest", "permissions": permissions})
+	dashboardId := api.addToken(map[string]any{"description": "signals dashboard", "permissions": permissions})
+
+	d := &TokensDataSource{accountID: api.accountID, client: api.client, clusterID: api.clusterID}
+	schemaResp := &datasource.SchemaResponse{}
+	d.Schema(t.Context(), datasource.SchemaRequest{}, schemaResp)
+	objectType, ok := schemaResp.Schema.Type().TerraformType(t.Context()).(tftypes.Object)
+	if !ok {
+		t.Fatalf("expected an object schema, got %s", schemaResp.Schema.Type())
+	}
+	config := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		config[name] = tftypes.NewValue(attributeType, nil)
+	}
+
+	req := datasource.ReadRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, config)}}
+	resp := &datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)}}
+	d.Read(t.Context(), req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	var state TokensDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(t.Context(), &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	expected := map[string][]types.String{
+		"signals ingest":    {types.StringValue(firstIngestId), types.StringValue(secondIngestId)},
+		"signals dashboard": {types.StringValue(dashboardId)},
+	}
+	if len(state.IdsByDescription) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, state.IdsByDescription)
+	}
+	for description, ids := range expected {
+		if !slices.Equal(state.IdsByDescription[description], ids) {
+			t.Errorf("expected %s to map to %v, got %v", description, ids, state.IdsByDescription[description])
+		}
+	}
+}
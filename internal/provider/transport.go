@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// readOnlyTransport refuses every request that could change a cluster. It
+// backs up the read-only checks of the resources.
+type readOnlyTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("refusing %s %s: the provider is read-only", req.Method, req.URL.Path)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// readOnlyDiagnostic returns the error reported when a read-only provider is
+// asked to change a resource.
+func readOnlyDiagnostic(operation string, resource string) diag.Diagnostic {
+	return diag.NewErrorDiagnostic(
+		"Provider is read-only",
+		fmt.Sprintf("Cannot %s %s: the provider is configured with read_only or INFLUXDB3_READ_ONLY, so it never changes the cluster. Unset read_only to apply changes.", operation, resource),
+	)
+}
+
+// limitTransport limits the number of requests in flight and the rate at
+// which requests are sent. It sits below the retrying client, so every
+// attempt counts against the limits, and is shared by all resources and data
+// sources of a provider instance.
+type limitTransport struct {
+	next http.RoundTripper
+
+	// slots holds a value for every request in flight, or is nil when the
+	// concurrency is unlimited.
+	slots chan struct{}
+
+	// interval is the time between requests, or 0 when the rate is unlimited.
+	interval time.Duration
+	mu       sync.Mutex
+	nextSend time.Time
+}
+
+// newLimitTransport returns a transport allowing maxConcurrentRequests
+// requests in flight and requestsPerSecond requests per second. Limits of 0
+// are unlimited.
+func newLimitTransport(next http.RoundTripper, maxConcurrentRequests int64, requestsPerSecond float64) *limitTransport {
+	t := &limitTransport{next: next}
+	if maxConcurrentRequests > 0 {
+		t.slots = make(chan struct{}, maxConcurrentRequests)
+	}
+	if requestsPerSecond > 0 {
+		t.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	return t
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+		default:
+			start := time.Now()
+			tflog.Debug(ctx, "Waiting for a request slot", map[string]any{"max_concurrent_requests": cap(t.slots)})
+			select {
+			case t.slots <- struct{}{}:
+			case <-ctx.Done():
+				return nil, closeRequestBody(req, ctx.Err())
+			}
+			tflog.Debug(ctx, "Acquired a request slot", map[string]any{"wait": time.Since(start).String()})
+		}
+	}
+
+	if err := t.waitForRate(ctx); err != nil {
+		t.release()
+		return nil, closeRequestBody(req, err)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.release()
+		return nil, err
+	}
+
+	// Hold the slot until the response has been read
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: t.release}
+	return resp, nil
+}
+
+// waitForRate waits until the request may be sent under the rate limit.
+func (t *limitTransport) waitForRate(ctx context.Context) error {
+	if t.interval <= 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	now := time.Now()
+	send := t.nextSend
+	if send.Before(now) {
+		send = now
+	}
+	t.nextSend = send.Add(t.interval)
+	t.mu.Unlock()
+
+	wait := send.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+
+	tflog.Debug(ctx, "Waiting for the request rate limit", map[string]any{"wait": wait.String()})
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *limitTransport) release() {
+	if t.slots != nil {
+		<-t.slots
+	}
+}
+
+// releaseOnClose calls release once when the body is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+// Close implements io.Closer.
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// closeRequestBody closes the body of a request that is not sent, as
+// RoundTrip must, and returns err.
+func closeRequestBody(req *http.Request, err error) error {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return err
+}
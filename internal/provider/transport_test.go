@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReadOnlyTransport(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &readOnlyTransport{next: http.DefaultTransport}}
+
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		req, err := http.NewRequestWithContext(t.Context(), method, server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("expected %s to be allowed, got: %s", method, err)
+		}
+		resp.Body.Close()
+	}
+
+	for _, method := range []string{http.MethodPost, http.MethodPatch, http.MethodDelete} {
+		req, err := http.NewRequestWithContext(t.Context(), method, server.URL, strings.NewReader("{}"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			t.Fatalf("expected %s to be refused", method)
+		}
+	}
+
+	if requests != 2 {
+		t.Errorf("expected 2 requests to reach the server, got %d", requests)
+	}
+}
+
+func TestLimitTransport(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	testCases := map[string]struct {
+		maxConcurrentRequests int64
+		requestsPerSecond     float64
+		expectedMaxInFlight   int32
+		expectedMinDuration   time.Duration
+	}{
+		"concurrency": {
+			maxConcurrentRequests: 2,
+			expectedMaxInFlight:   2,
+		},
+		"rate": {
+			requestsPerSecond:   50,
+			expectedMaxInFlight: 6,
+			expectedMinDuration: 100 * time.Millisecond,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			maxInFlight.Store(0)
+			client := &http.Client{Transport: newLimitTransport(http.DefaultTransport, testCase.maxConcurrentRequests, testCase.requestsPerSecond)}
+
+			start := time.Now()
+			var wg sync.WaitGroup
+			for range 6 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					resp, err := client.Get(server.URL)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					io.Copy(io.Discard, resp.Body)
+					resp.Body.Close()
+				}()
+			}
+			wg.Wait()
+
+			if got := maxInFlight.Load(); got > testCase.expectedMaxInFlight {
+				t.Errorf("expected at most %d requests in flight, got %d", testCase.expectedMaxInFlight, got)
+			}
+			if elapsed := time.Since(start); elapsed < testCase.expectedMinDuration {
+				t.Errorf("expected the requests to take at least %s, took %s", testCase.expectedMinDuration, elapsed)
+			}
+		})
+	}
+}
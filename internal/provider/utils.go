@@ -1,10 +1,15 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strconv"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/thulasirajkomminar/influxdb3-management-go"
 )
 
@@ -31,3 +36,48 @@ func formatErrorResponse(rsp any, statusCode int) (string, error) {
 	}
 	return fmt.Sprintf("HTTP Status Code: %d\nError Code: %d\nError Message: %s\n", statusCode, errorDetail.Code, errorDetail.Message), nil
 }
+
+// isLegacyProviderAddress reports whether a provider address in HOSTNAME/NAMESPACE/TYPE
+// format refers to the provider's former komminarlabs/influxdb3 namespace.
+func isLegacyProviderAddress(address string) bool {
+	parts := strings.Split(address, "/")
+	if len(parts) < 2 {
+		return false
+	}
+	return strings.Join(parts[len(parts)-2:], "/") == INFLUXDB3_LEGACY_PROVIDER
+}
+
+// isFullyKnown reports whether a value, including any nested elements or
+// attributes, is known.
+func isFullyKnown(ctx context.Context, value attr.Value) bool {
+	tfValue, err := value.ToTerraformValue(ctx)
+	if err != nil {
+		return false
+	}
+	return tfValue.IsFullyKnown()
+}
+
+type regexpValidator struct{}
+
+func (v regexpValidator) Description(ctx context.Context) string {
+	return "value must be a valid regular expression"
+}
+
+func (v regexpValidator) MarkdownDescription(ctx context.Context) string {
+	return "value must be a valid regular expression"
+}
+
+func (v regexpValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	_, err := regexp.Compile(req.ConfigValue.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Regular Expression",
+			fmt.Sprintf("The value must be a valid regular expression. Error: %s", err.Error()),
+		)
+	}
+}